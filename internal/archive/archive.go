@@ -0,0 +1,232 @@
+package archive
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ManifestFile is the name of the manifest written into every batch folder
+const ManifestFile = "manifest.yml"
+
+// Manifest records one release or abandonment batch
+type Manifest struct {
+	Type    string         `yaml:"type"`
+	Version string         `yaml:"version,omitempty"`
+	Reason  string         `yaml:"reason,omitempty"`
+	Date    string         `yaml:"date"`
+	Source  string         `yaml:"source"`
+	Items   []ManifestItem `yaml:"items"`
+}
+
+// ManifestItem is a single archived work item within a batch
+type ManifestItem struct {
+	ID    string `yaml:"id"`
+	Title string `yaml:"title"`
+	Kind  string `yaml:"kind"`
+	From  string `yaml:"from"`
+	Path  string `yaml:"path"`
+}
+
+// Batch is an archived batch, either still a folder on disk or compacted
+// into a bundle
+type Batch struct {
+	ID       string // path relative to the archive root, e.g. 2025-01-15/4_done
+	Dir      string // folder on disk; empty once compacted
+	Bundle   string // bundle file holding the batch after compaction
+	Date     time.Time
+	Manifest Manifest
+}
+
+// Compacted reports whether the batch only lives in a bundle
+func (b Batch) Compacted() bool {
+	return b.Bundle != ""
+}
+
+// Release returns the label used when grouping by release
+func (b Batch) Release() string {
+	if b.Manifest.Version != "" {
+		return b.Manifest.Version
+	}
+	if b.Manifest.Type == "" {
+		return "(unknown)"
+	}
+	return "(" + b.Manifest.Type + ", no version)"
+}
+
+// ReadManifest loads the manifest of a batch folder
+func ReadManifest(dir string) (Manifest, error) {
+	var m Manifest
+	data, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil {
+		return m, err
+	}
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return m, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, ManifestFile), err)
+	}
+	return m, nil
+}
+
+// WriteManifest stores the manifest of a batch folder
+func WriteManifest(dir string, m Manifest) error {
+	data, err := yaml.Marshal(&m)
+	if err != nil {
+		return fmt.Errorf("failed to encode archive manifest: %w", err)
+	}
+	return os.WriteFile(filepath.Join(dir, ManifestFile), data, 0644)
+}
+
+// ListBatches returns every batch under root, oldest first. Folders with a
+// manifest are batches; older folders archived before manifests existed are
+// recognised as <date>/<name>. Compacted batches are read back from bundles.
+func ListBatches(root, dateFormat string) ([]Batch, error) {
+	var batches []Batch
+
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() || path == root {
+			return nil
+		}
+		if filepath.Base(path) == BundleDir {
+			return filepath.SkipDir
+		}
+
+		rel, _ := filepath.Rel(root, path)
+		m, err := ReadManifest(path)
+		switch {
+		case err == nil:
+		case os.IsNotExist(err) && isLegacyBatch(root, path):
+			m = legacyManifest(path)
+		case os.IsNotExist(err):
+			return nil
+		default:
+			return err
+		}
+
+		batches = append(batches, Batch{
+			ID:       filepath.ToSlash(rel),
+			Dir:      path,
+			Date:     batchDate(m, rel, dateFormat, info.ModTime()),
+			Manifest: m,
+		})
+		return filepath.SkipDir
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bundled, err := bundledBatches(root)
+	if err != nil {
+		return nil, err
+	}
+	batches = append(batches, bundled...)
+
+	sort.SliceStable(batches, func(i, j int) bool {
+		if batches[i].Date.Equal(batches[j].Date) {
+			return batches[i].ID < batches[j].ID
+		}
+		return batches[i].Date.Before(batches[j].Date)
+	})
+	return batches, nil
+}
+
+// FindBatch looks a batch up by its ID
+func FindBatch(root, dateFormat, id string) (Batch, error) {
+	batches, err := ListBatches(root, dateFormat)
+	if err != nil {
+		return Batch{}, err
+	}
+	id = strings.Trim(filepath.ToSlash(id), "/")
+	for _, b := range batches {
+		if b.ID == id {
+			return b, nil
+		}
+	}
+	return Batch{}, fmt.Errorf("archive batch %s not found", id)
+}
+
+// isLegacyBatch reports whether path is a <date>/<name> folder of work items
+// two levels below the archive root, the flat layout used before manifests
+func isLegacyBatch(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil || len(strings.Split(filepath.ToSlash(rel), "/")) != 2 {
+		return false
+	}
+	matches, _ := filepath.Glob(filepath.Join(path, "*.md"))
+	return len(matches) > 0
+}
+
+func legacyManifest(dir string) Manifest {
+	m := Manifest{Source: filepath.Base(dir)}
+	_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(path, ".md") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		m.Items = append(m.Items, ManifestItem{
+			ID:    FrontMatterField(string(content), "id"),
+			Title: FrontMatterField(string(content), "title"),
+			Kind:  FrontMatterField(string(content), "kind"),
+			Path:  path,
+		})
+		return nil
+	})
+	return m
+}
+
+// Records reads every item of an on-disk batch into bundle records
+func Records(b Batch) ([]Record, error) {
+	var records []Record
+	for _, item := range b.Manifest.Items {
+		content, err := os.ReadFile(item.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", item.Path, err)
+		}
+		rel, err := filepath.Rel(b.Dir, item.Path)
+		if err != nil {
+			rel = filepath.Base(item.Path)
+		}
+		records = append(records, Record{
+			Batch:   b.ID,
+			Type:    b.Manifest.Type,
+			Version: b.Manifest.Version,
+			Reason:  b.Manifest.Reason,
+			Date:    b.Date.UTC().Format(time.RFC3339),
+			Source:  b.Manifest.Source,
+			ID:      item.ID,
+			Title:   item.Title,
+			Kind:    item.Kind,
+			Path:    filepath.ToSlash(rel),
+			Content: string(content),
+		})
+	}
+	return records, nil
+}
+
+func batchDate(m Manifest, rel, dateFormat string, fallback time.Time) time.Time {
+	if t, err := time.Parse(time.RFC3339, m.Date); err == nil {
+		return t
+	}
+	// The date folder may itself contain slashes, so try successively longer prefixes
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	for i := 1; i < len(parts); i++ {
+		if t, err := time.Parse(dateFormat, strings.Join(parts[:i], "/")); err == nil {
+			return t
+		}
+	}
+	return fallback
+}
@@ -0,0 +1,78 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	records := []Record{
+		{Batch: "2024-01-15/4_done", Type: "release", Version: "v1", Date: "2024-01-15T10:00:00Z", ID: "001", Title: "One", Kind: "prd", Path: "001-one.prd.md", Content: "---\nid: 001\n---\n# One\n\n<!-- comment -->\n"},
+		{Batch: "2024-02-01/002", Type: "abandon", Reason: "dup", Date: "2024-02-01T10:00:00Z", ID: "002", Title: "Two", Kind: "issue", Path: "002-two.issue.md", Content: "---\nid: 002\n---\n"},
+	}
+
+	for _, format := range []string{FormatMarkdown, FormatJSONL} {
+		t.Run(format, func(t *testing.T) {
+			root := t.TempDir()
+			path := BundlePath(root, format, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC))
+			assert.Contains(t, path, "2024-Q1")
+			assert.True(t, IsBundle(path))
+
+			require.NoError(t, AppendToBundle(path, records[:1]))
+			require.NoError(t, AppendToBundle(path, records[1:]))
+
+			got, err := ReadBundle(path)
+			require.NoError(t, err)
+			require.Len(t, got, 2)
+			assert.Equal(t, "001", got[0].ID)
+			assert.Equal(t, "dup", got[1].Reason)
+			assert.Equal(t, "---\nid: 001\n---\n# One\n\n<!-- comment -->", strings.TrimRight(got[0].Content, "\n"))
+
+			require.NoError(t, WriteBundle(path, nil))
+			assert.NoFileExists(t, path)
+		})
+	}
+}
+
+func TestListBatches(t *testing.T) {
+	root := t.TempDir()
+
+	batchDir := filepath.Join(root, "2024-03-01", "4_done")
+	require.NoError(t, os.MkdirAll(filepath.Join(batchDir, "v2"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(batchDir, "v2", "003-three.prd.md"), []byte("---\nid: 003\n---\n"), 0644))
+	require.NoError(t, WriteManifest(batchDir, Manifest{
+		Type:    "release",
+		Version: "v2",
+		Date:    "2024-03-01T09:00:00Z",
+		Items:   []ManifestItem{{ID: "003", Path: filepath.Join(batchDir, "v2", "003-three.prd.md")}},
+	}))
+
+	legacyDir := filepath.Join(root, "2023-12-24", "1_todo")
+	require.NoError(t, os.MkdirAll(legacyDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(legacyDir, "004-four.task.md"), []byte("---\nid: 004\ntitle: Four\n---\n"), 0644))
+
+	require.NoError(t, WriteBundle(BundlePath(root, FormatMarkdown, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)), []Record{
+		{Batch: "2023-01-02/4_done", Type: "release", Version: "v0", Date: "2023-01-02T00:00:00Z", ID: "001", Path: "001-one.prd.md", Content: "x"},
+	}))
+
+	batches, err := ListBatches(root, "2006-01-02")
+	require.NoError(t, err)
+	require.Len(t, batches, 3)
+
+	assert.Equal(t, "2023-01-02/4_done", batches[0].ID)
+	assert.True(t, batches[0].Compacted())
+	assert.Equal(t, "2023-12-24/1_todo", batches[1].ID)
+	assert.Equal(t, "004", batches[1].Manifest.Items[0].ID)
+	assert.Equal(t, "2024-03-01/4_done", batches[2].ID)
+	assert.Equal(t, "v2", batches[2].Release())
+
+	records, err := Records(batches[2])
+	require.NoError(t, err)
+	assert.Equal(t, "v2/003-three.prd.md", records[0].Path)
+}
@@ -0,0 +1,245 @@
+package archive
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// BundleDir is the folder under the archive root that holds compacted batches
+	BundleDir = "bundles"
+
+	FormatMarkdown = "markdown"
+	FormatJSONL    = "jsonl"
+
+	markdownBundleExt = ".bundle.md"
+	jsonlBundleExt    = ".jsonl"
+
+	recordStart = "<!-- kira-record: "
+	recordEnd   = "<!-- kira-record-end -->"
+)
+
+// Record is one archived work item stored in a bundle, together with the
+// batch it came from so the batch can still be listed and restored
+type Record struct {
+	Batch   string `json:"batch"`
+	Type    string `json:"type,omitempty"`
+	Version string `json:"version,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+	Date    string `json:"date"`
+	Source  string `json:"source,omitempty"`
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Kind    string `json:"kind"`
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"`
+}
+
+// IsBundle reports whether path is a compacted archive bundle. Bundles hold
+// many items and must never be treated as a single work item.
+func IsBundle(path string) bool {
+	if strings.HasSuffix(path, markdownBundleExt) {
+		return true
+	}
+	return strings.HasSuffix(path, jsonlBundleExt) && filepath.Base(filepath.Dir(path)) == BundleDir
+}
+
+// BundlePath returns the bundle file for the quarter containing t
+func BundlePath(root, format string, t time.Time) string {
+	name := fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	if format == FormatJSONL {
+		return filepath.Join(root, BundleDir, name+jsonlBundleExt)
+	}
+	return filepath.Join(root, BundleDir, name+markdownBundleExt)
+}
+
+// ListBundles returns the bundle files under root
+func ListBundles(root string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(root, BundleDir))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var bundles []string
+	for _, e := range entries {
+		path := filepath.Join(root, BundleDir, e.Name())
+		if !e.IsDir() && IsBundle(path) {
+			bundles = append(bundles, path)
+		}
+	}
+	sort.Strings(bundles)
+	return bundles, nil
+}
+
+// ReadBundle loads every record from a markdown or JSONL bundle
+func ReadBundle(path string) ([]Record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	if strings.HasSuffix(path, jsonlBundleExt) {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var r Record
+			if err := json.Unmarshal([]byte(line), &r); err != nil {
+				return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+			}
+			records = append(records, r)
+		}
+		return records, scanner.Err()
+	}
+
+	rest := string(data)
+	for {
+		start := strings.Index(rest, recordStart)
+		if start < 0 {
+			break
+		}
+		rest = rest[start+len(recordStart):]
+		headerEnd := strings.Index(rest, " -->\n")
+		if headerEnd < 0 {
+			return nil, fmt.Errorf("failed to parse %s: unterminated record header", path)
+		}
+		var r Record
+		if err := json.Unmarshal([]byte(rest[:headerEnd]), &r); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		rest = rest[headerEnd+len(" -->\n"):]
+		end := strings.Index(rest, "\n"+recordEnd)
+		if end < 0 {
+			return nil, fmt.Errorf("failed to parse %s: unterminated record %s", path, r.ID)
+		}
+		r.Content = rest[:end]
+		rest = rest[end+len(recordEnd)+1:]
+		records = append(records, r)
+	}
+	return records, nil
+}
+
+// WriteBundle replaces the contents of a bundle with records. An empty list
+// removes the bundle file.
+func WriteBundle(path string, records []Record) error {
+	if len(records) == 0 {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if strings.HasSuffix(path, jsonlBundleExt) {
+		for _, r := range records {
+			line, err := json.Marshal(r)
+			if err != nil {
+				return err
+			}
+			buf.Write(line)
+			buf.WriteByte('\n')
+		}
+		return os.WriteFile(path, buf.Bytes(), 0644)
+	}
+
+	name := strings.TrimSuffix(filepath.Base(path), markdownBundleExt)
+	fmt.Fprintf(&buf, "# Archive bundle %s\n\nCompacted archive batches. Use `kira archive restore <id>` to bring an item back.\n", name)
+	for _, r := range records {
+		content := r.Content
+		r.Content = ""
+		header, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&buf, "\n%s%s -->\n%s\n%s\n", recordStart, header, strings.TrimRight(content, "\n"), recordEnd)
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// AppendToBundle adds records to a bundle, creating it if needed
+func AppendToBundle(path string, records []Record) error {
+	existing, err := ReadBundle(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return WriteBundle(path, append(existing, records...))
+}
+
+// bundledBatches rebuilds the batch list from the records in every bundle
+func bundledBatches(root string) ([]Batch, error) {
+	bundles, err := ListBundles(root)
+	if err != nil {
+		return nil, err
+	}
+
+	var batches []Batch
+	for _, bundle := range bundles {
+		records, err := ReadBundle(bundle)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[string]int)
+		for _, r := range records {
+			idx, ok := byID[r.Batch]
+			if !ok {
+				date, _ := time.Parse(time.RFC3339, r.Date)
+				batches = append(batches, Batch{
+					ID:     r.Batch,
+					Bundle: bundle,
+					Date:   date,
+					Manifest: Manifest{
+						Type:    r.Type,
+						Version: r.Version,
+						Reason:  r.Reason,
+						Date:    r.Date,
+						Source:  r.Source,
+					},
+				})
+				idx = len(batches) - 1
+				byID[r.Batch] = idx
+			}
+			batches[idx].Manifest.Items = append(batches[idx].Manifest.Items, ManifestItem{
+				ID:    r.ID,
+				Title: r.Title,
+				Kind:  r.Kind,
+				Path:  r.Path,
+			})
+		}
+	}
+	return batches, nil
+}
+
+// FrontMatterField returns the value of a top-level front matter key using
+// the same line-based matching kira uses when editing work items
+func FrontMatterField(content, key string) string {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return ""
+	}
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "---" {
+			break
+		}
+		if strings.HasPrefix(line, key+":") {
+			return strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, key+":")), `"'`)
+		}
+	}
+	return ""
+}
@@ -40,6 +40,7 @@ Updates work item status to "abandoned" before archival.`,
 func abandonWorkItems(cfg *config.Config, target, reasonOrSubfolder string) error {
 	var workItems []string
 	var sourcePath string
+	batch := archiveBatch{Type: "abandon"}
 
 	// Check if target is a work item ID or a path
 	if isWorkItemID(target) {
@@ -50,6 +51,7 @@ func abandonWorkItems(cfg *config.Config, target, reasonOrSubfolder string) erro
 		}
 		workItems = []string{workItemPath}
 		sourcePath = filepath.Dir(workItemPath)
+		batch.Name = target
 	} else {
 		// Target is a path
 		if strings.Contains(target, "/") {
@@ -69,6 +71,8 @@ func abandonWorkItems(cfg *config.Config, target, reasonOrSubfolder string) erro
 			sourcePath = filepath.Join(sourcePath, reasonOrSubfolder)
 		}
 
+		batch.Name = filepath.Base(sourcePath)
+
 		// Get all work item files in the source path
 		var err error
 		workItems, err = getWorkItemFiles(sourcePath)
@@ -82,6 +86,11 @@ func abandonWorkItems(cfg *config.Config, target, reasonOrSubfolder string) erro
 		return nil
 	}
 
+	batch.Source = sourcePath
+	if reasonOrSubfolder != "" && strings.Contains(reasonOrSubfolder, " ") {
+		batch.Reason = reasonOrSubfolder
+	}
+
 	// Update work item statuses to "abandoned" and add reason if provided
 	for _, workItem := range workItems {
 		if err := updateWorkItemStatus(workItem, "abandoned"); err != nil {
@@ -97,18 +106,11 @@ func abandonWorkItems(cfg *config.Config, target, reasonOrSubfolder string) erro
 	}
 
 	// Archive work items
-	archivePath, err := archiveWorkItems(workItems, sourcePath)
+	archivePath, err := archiveWorkItems(cfg, workItems, batch)
 	if err != nil {
 		return fmt.Errorf("failed to archive work items: %w", err)
 	}
 
-	// Remove original files
-	for _, workItem := range workItems {
-		if err := os.Remove(workItem); err != nil {
-			fmt.Printf("Warning: failed to remove %s: %v\n", workItem, err)
-		}
-	}
-
 	fmt.Printf("Abandoned %d work items to %s\n", len(workItems), archivePath)
 	return nil
 }
@@ -0,0 +1,407 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"kira/internal/archive"
+	"kira/internal/config"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "Browse, search, compact and restore archived work items",
+	Long: `Browses the archive of released and abandoned work items. Batches older than
+archive.compact_after_months in kira.yml can be compacted into one bundle per
+quarter; compacted items remain searchable and restorable.`,
+}
+
+var archiveListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List archive batches grouped by date or release",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		by, _ := cmd.Flags().GetString("by")
+		return listArchive(cfg, by)
+	},
+}
+
+var archiveShowCmd = &cobra.Command{
+	Use:   "show <batch>",
+	Short: "Show the manifest and items of an archive batch",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		return showArchiveBatch(cfg, args[0])
+	},
+}
+
+var archiveCompactCmd = &cobra.Command{
+	Use:   "compact",
+	Short: "Bundle old archive batches into one file per quarter",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		months := cfg.Archive.CompactAfterMonths
+		if cmd.Flags().Changed("older-than") {
+			months, _ = cmd.Flags().GetInt("older-than")
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		return compactArchive(cfg, months, dryRun)
+	},
+}
+
+var archiveSearchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search archived and compacted work items",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		return searchArchive(cfg, args[0])
+	},
+}
+
+var archiveRestoreCmd = &cobra.Command{
+	Use:   "restore <work-item-id> [status]",
+	Short: "Move an archived or compacted work item back into a status folder",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		status := cfg.DefaultStatus
+		if len(args) > 1 {
+			status = args[1]
+		}
+		return restoreArchivedItem(cfg, args[0], status)
+	},
+}
+
+func init() {
+	archiveListCmd.Flags().String("by", "date", "Group batches by 'date' or 'release'")
+	archiveCompactCmd.Flags().Int("older-than", 0, "Compact batches older than this many months (overrides archive.compact_after_months)")
+	archiveCompactCmd.Flags().Bool("dry-run", false, "Show which batches would be compacted")
+
+	archiveCmd.AddCommand(archiveListCmd)
+	archiveCmd.AddCommand(archiveShowCmd)
+	archiveCmd.AddCommand(archiveCompactCmd)
+	archiveCmd.AddCommand(archiveSearchCmd)
+	archiveCmd.AddCommand(archiveRestoreCmd)
+}
+
+func archiveRoot(cfg *config.Config) string {
+	return filepath.Join(".work", cfg.StatusFolders["archived"])
+}
+
+func listArchive(cfg *config.Config, by string) error {
+	batches, err := archive.ListBatches(archiveRoot(cfg), cfg.Release.ArchiveDateFormat)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+	if len(batches) == 0 {
+		fmt.Println("Archive is empty.")
+		return nil
+	}
+
+	var groupOf func(archive.Batch) string
+	switch by {
+	case "date":
+		groupOf = func(b archive.Batch) string { return b.Date.Format(cfg.Release.ArchiveDateFormat) }
+	case "release":
+		groupOf = archive.Batch.Release
+	default:
+		return fmt.Errorf("invalid grouping: %s (use 'date' or 'release')", by)
+	}
+
+	var order []string
+	groups := make(map[string][]archive.Batch)
+	for _, b := range batches {
+		key := groupOf(b)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], b)
+	}
+
+	for _, key := range order {
+		fmt.Println(key)
+		for _, b := range groups[key] {
+			kind := b.Manifest.Type
+			if kind == "" {
+				kind = "archive"
+			}
+			if b.Manifest.Version != "" {
+				kind += " " + b.Manifest.Version
+			}
+			line := fmt.Sprintf("  %-28s %-20s %d items", b.ID, kind, len(b.Manifest.Items))
+			if b.Compacted() {
+				line += " (compacted)"
+			}
+			fmt.Println(line)
+		}
+	}
+	return nil
+}
+
+func showArchiveBatch(cfg *config.Config, id string) error {
+	b, err := archive.FindBatch(archiveRoot(cfg), cfg.Release.ArchiveDateFormat, id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Batch: %s\n", b.ID)
+	if b.Manifest.Type != "" {
+		fmt.Printf("Type: %s\n", b.Manifest.Type)
+	}
+	if b.Manifest.Version != "" {
+		fmt.Printf("Version: %s\n", b.Manifest.Version)
+	}
+	if b.Manifest.Reason != "" {
+		fmt.Printf("Reason: %s\n", b.Manifest.Reason)
+	}
+	fmt.Printf("Date: %s\n", b.Date.Format(time.RFC3339))
+	if b.Manifest.Source != "" {
+		fmt.Printf("Source: %s\n", b.Manifest.Source)
+	}
+	if b.Compacted() {
+		fmt.Printf("Location: %s (compacted)\n", b.Bundle)
+	} else {
+		fmt.Printf("Location: %s\n", b.Dir)
+	}
+
+	fmt.Println("Items:")
+	for _, item := range b.Manifest.Items {
+		fmt.Printf("  %s %s (%s) %s\n", item.ID, item.Title, item.Kind, item.Path)
+	}
+	return nil
+}
+
+// compactArchive moves every on-disk batch older than the given number of
+// months into its quarter's bundle and removes the batch folder
+func compactArchive(cfg *config.Config, months int, dryRun bool) error {
+	if months <= 0 {
+		fmt.Println("Archive compaction is disabled (set archive.compact_after_months or pass --older-than).")
+		return nil
+	}
+
+	root := archiveRoot(cfg)
+	batches, err := archive.ListBatches(root, cfg.Release.ArchiveDateFormat)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	cutoff := time.Now().AddDate(0, -months, 0)
+	useGit := isGitRepo()
+	compacted := 0
+	for _, b := range batches {
+		if b.Compacted() || !b.Date.Before(cutoff) {
+			continue
+		}
+
+		bundle := archive.BundlePath(root, cfg.Archive.BundleFormat, b.Date)
+		if dryRun {
+			fmt.Printf("Would compact %s into %s\n", b.ID, bundle)
+			compacted++
+			continue
+		}
+
+		records, err := archive.Records(b)
+		if err != nil {
+			return err
+		}
+		if err := archive.AppendToBundle(bundle, records); err != nil {
+			return fmt.Errorf("failed to write bundle %s: %w", bundle, err)
+		}
+		if err := removeArchiveDir(root, b.Dir, useGit); err != nil {
+			return err
+		}
+		fmt.Printf("Compacted %s into %s\n", b.ID, bundle)
+		compacted++
+	}
+
+	if compacted == 0 {
+		fmt.Printf("No archive batches older than %d months.\n", months)
+	}
+	return nil
+}
+
+// removeArchiveDir deletes a batch folder, through git when it is tracked, and
+// prunes any date folders left empty
+func removeArchiveDir(root, dir string, useGit bool) error {
+	if useGit {
+		if _, err := runGit("rm", "-r", "-q", "-f", "--ignore-unmatch", "--", dir); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", dir, err)
+	}
+
+	for parent := filepath.Dir(dir); parent != root && strings.HasPrefix(parent, root); parent = filepath.Dir(parent) {
+		entries, err := os.ReadDir(parent)
+		if err != nil || len(entries) > 0 {
+			break
+		}
+		if err := os.Remove(parent); err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+func searchArchive(cfg *config.Config, query string) error {
+	root := archiveRoot(cfg)
+	batches, err := archive.ListBatches(root, cfg.Release.ArchiveDateFormat)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	needle := strings.ToLower(query)
+	matches := 0
+	report := func(id, title, batch, content string, compacted bool) {
+		if !strings.Contains(strings.ToLower(content), needle) && !strings.Contains(strings.ToLower(title), needle) && id != query {
+			return
+		}
+		line := fmt.Sprintf("%s %s [%s]", id, title, batch)
+		if compacted {
+			line += " (compacted)"
+		}
+		fmt.Println(line)
+		matches++
+	}
+
+	for _, b := range batches {
+		if b.Compacted() {
+			continue
+		}
+		for _, item := range b.Manifest.Items {
+			content, err := os.ReadFile(item.Path)
+			if err != nil {
+				continue
+			}
+			report(item.ID, item.Title, b.ID, string(content), false)
+		}
+	}
+
+	bundles, err := archive.ListBundles(root)
+	if err != nil {
+		return err
+	}
+	for _, bundle := range bundles {
+		records, err := archive.ReadBundle(bundle)
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			report(r.ID, r.Title, r.Batch, r.Content, true)
+		}
+	}
+
+	if matches == 0 {
+		fmt.Printf("No archived work items match %q.\n", query)
+	}
+	return nil
+}
+
+// restoreArchivedItem brings an archived item back into a status folder,
+// taking it out of its batch folder or bundle
+func restoreArchivedItem(cfg *config.Config, id, status string) error {
+	statusFolder, ok := cfg.StatusFolders[status]
+	if !ok || status == "archived" {
+		return fmt.Errorf("invalid target status: %s", status)
+	}
+	targetDir := filepath.Join(".work", statusFolder)
+
+	root := archiveRoot(cfg)
+	batches, err := archive.ListBatches(root, cfg.Release.ArchiveDateFormat)
+	if err != nil {
+		return fmt.Errorf("failed to read archive: %w", err)
+	}
+
+	for _, b := range batches {
+		if b.Compacted() {
+			continue
+		}
+		for i, item := range b.Manifest.Items {
+			if item.ID != id {
+				continue
+			}
+			target := filepath.Join(targetDir, filepath.Base(item.Path))
+			if _, err := os.Stat(target); err == nil {
+				return fmt.Errorf("cannot restore %s: %s already exists", id, target)
+			}
+			if err := moveFile(item.Path, target, isGitRepo()); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", id, err)
+			}
+			if err := updateWorkItemStatus(target, status); err != nil {
+				return fmt.Errorf("failed to update work item status: %w", err)
+			}
+
+			b.Manifest.Items = append(b.Manifest.Items[:i], b.Manifest.Items[i+1:]...)
+			if len(b.Manifest.Items) == 0 {
+				if err := removeArchiveDir(root, b.Dir, isGitRepo()); err != nil {
+					return err
+				}
+			} else if b.Manifest.Type != "" {
+				if err := archive.WriteManifest(b.Dir, b.Manifest); err != nil {
+					return err
+				}
+			}
+
+			fmt.Printf("Restored work item %s from %s to %s\n", id, b.ID, status)
+			return nil
+		}
+	}
+
+	bundles, err := archive.ListBundles(root)
+	if err != nil {
+		return err
+	}
+	for _, bundle := range bundles {
+		records, err := archive.ReadBundle(bundle)
+		if err != nil {
+			return err
+		}
+		for i, r := range records {
+			if r.ID != id {
+				continue
+			}
+			target := filepath.Join(targetDir, filepath.Base(r.Path))
+			if _, err := os.Stat(target); err == nil {
+				return fmt.Errorf("cannot restore %s: %s already exists", id, target)
+			}
+			if err := os.WriteFile(target, []byte(strings.TrimRight(r.Content, "\n")+"\n"), 0644); err != nil {
+				return fmt.Errorf("failed to restore %s: %w", id, err)
+			}
+			if err := updateWorkItemStatus(target, status); err != nil {
+				return fmt.Errorf("failed to update work item status: %w", err)
+			}
+			if err := archive.WriteBundle(bundle, append(records[:i], records[i+1:]...)); err != nil {
+				return fmt.Errorf("failed to update bundle %s: %w", bundle, err)
+			}
+
+			fmt.Printf("Restored work item %s from %s (%s) to %s\n", id, r.Batch, bundle, status)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("archived work item with ID %s not found", id)
+}
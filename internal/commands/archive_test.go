@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/archive"
+	"kira/internal/config"
+	"kira/internal/validation"
+)
+
+func TestCompactAndRestoreArchive(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+
+	cfg := &config.DefaultConfig
+	os.MkdirAll(".work/1_todo", 0755)
+	os.MkdirAll(".work/4_done", 0755)
+	path := writeDoneItem(t, "007", "prd", "[ui]")
+
+	archivePath, err := archiveWorkItems(cfg, []string{path}, archiveBatch{Type: "release", Name: "4_done", Source: ".work/4_done", Version: "v1"})
+	require.NoError(t, err)
+
+	// Age the batch so it falls outside the retention window
+	manifest, err := archive.ReadManifest(archivePath)
+	require.NoError(t, err)
+	manifest.Date = "2020-01-10T00:00:00Z"
+	require.NoError(t, archive.WriteManifest(archivePath, manifest))
+
+	require.NoError(t, compactArchive(cfg, 6, false))
+	assert.NoDirExists(t, archivePath)
+	bundle := filepath.Join(".work", "z_archive", "bundles", "2020-Q1.bundle.md")
+	assert.FileExists(t, bundle)
+
+	// Compacted items keep their IDs reserved and are not linted as work items
+	nextID, err := validation.GetNextID()
+	require.NoError(t, err)
+	assert.Equal(t, "008", nextID)
+	result, err := validation.ValidateWorkItems(cfg)
+	require.NoError(t, err)
+	assert.False(t, result.HasErrors(), result.Error())
+
+	require.NoError(t, restoreArchivedItem(cfg, "007", "todo"))
+	restored := filepath.Join(".work", "1_todo", "007-item.prd.md")
+	content, err := os.ReadFile(restored)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "status: todo")
+	assert.Contains(t, string(content), "Notes for 007")
+	assert.NoFileExists(t, bundle)
+}
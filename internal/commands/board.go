@@ -0,0 +1,336 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"kira/internal/config"
+)
+
+var boardCmd = &cobra.Command{
+	Use:   "board",
+	Short: "Show work items as a board of status columns",
+	Long: `Shows the status folders as columns, in the order of their numeric prefix,
+with a card per work item giving its ID, title, assignee and tags. Column
+headers count every item in the folder, whatever the filters.
+
+With --interactive, pick a card with the arrow keys (or h/j/k/l) and move it
+to the previous or next column with < and > (or Shift+←/→). q quits.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		var filter itemFilter
+		filter.Assigned, _ = cmd.Flags().GetString("assigned")
+		filter.Tag, _ = cmd.Flags().GetString("tag")
+		filter.Kind, _ = cmd.Flags().GetString("kind")
+		interactive, _ := cmd.Flags().GetBool("interactive")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		if interactive {
+			return runBoard(cfg, filter)
+		}
+		columns, err := buildBoard(cfg, filter)
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(columns)
+		}
+		for _, line := range renderBoard(columns, terminalWidth(), -1, -1) {
+			fmt.Println(line)
+		}
+		return nil
+	},
+}
+
+func init() {
+	boardCmd.Flags().String("assigned", "", "Only show items assigned to this person")
+	boardCmd.Flags().String("tag", "", "Only show items with this tag")
+	boardCmd.Flags().String("kind", "", "Only show items of this kind")
+	boardCmd.Flags().BoolP("interactive", "i", false, "Move cards between columns with the keyboard")
+	boardCmd.Flags().Bool("json", false, "Print the board as JSON")
+}
+
+// boardColumn is a status folder on the board. Count is every item in the
+// folder; Items only those matching the filters.
+type boardColumn struct {
+	Status string        `json:"status"`
+	Folder string        `json:"folder"`
+	Count  int           `json:"count"`
+	Limit  int           `json:"wip_limit,omitempty"`
+	Items  []itemSummary `json:"items"`
+}
+
+// wipLimit is the most items a status should hold, from board.wip_limits
+// in kira.yml; 0 is unlimited
+func wipLimit(cfg *config.Config, status string) int {
+	return cfg.Board.WIPLimits[status]
+}
+
+func buildBoard(cfg *config.Config, filter itemFilter) ([]boardColumn, error) {
+	items, err := listItems(cfg, itemFilter{})
+	if err != nil {
+		return nil, err
+	}
+	var columns []boardColumn
+	index := make(map[string]int)
+	for _, status := range sortedStatuses(cfg) {
+		if status == "archived" {
+			continue
+		}
+		index[status] = len(columns)
+		columns = append(columns, boardColumn{Status: status, Folder: cfg.StatusFolders[status], Limit: wipLimit(cfg, status), Items: []itemSummary{}})
+	}
+	for _, item := range items {
+		c := &columns[index[item.Status]]
+		c.Count++
+		if filter.matches(item) {
+			c.Items = append(c.Items, item)
+		}
+	}
+	return columns, nil
+}
+
+// terminalWidth is the width of the terminal, or 80 when unknown
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if out, err := stty("size"); err == nil {
+		if fields := strings.Fields(out); len(fields) == 2 {
+			if n, err := strconv.Atoi(fields[1]); err == nil && n > 0 {
+				return n
+			}
+		}
+	}
+	return 80
+}
+
+// renderBoard lays the columns out side by side in width characters. The
+// card at selCol, selRow is highlighted; pass -1 for none.
+func renderBoard(columns []boardColumn, width, selCol, selRow int) []string {
+	const gap = 2
+	if len(columns) == 0 {
+		return nil
+	}
+	colWidth := (width - gap*(len(columns)-1)) / len(columns)
+	if colWidth < 14 {
+		colWidth = 14
+	}
+
+	cells := make([][]string, len(columns))
+	height := 0
+	for c, col := range columns {
+		header := fmt.Sprintf("%s (%d)", strings.ToUpper(col.Status), col.Count)
+		if col.Limit > 0 {
+			header = fmt.Sprintf("%s (%d/%d)", strings.ToUpper(col.Status), col.Count, col.Limit)
+			if col.Count > col.Limit {
+				header += " !"
+			}
+		}
+		lines := []string{fit(header, colWidth), strings.Repeat("─", colWidth)}
+		for r, item := range col.Items {
+			card := []string{item.ID + " " + item.Title}
+			if item.Assigned != "" {
+				card = append(card, "  @"+item.Assigned)
+			}
+			if len(item.Tags) > 0 {
+				card = append(card, "  #"+strings.Join(item.Tags, " #"))
+			}
+			for _, line := range card {
+				line = fit(line, colWidth)
+				if c == selCol && r == selRow {
+					line = "\x1b[7m" + line + "\x1b[0m"
+				}
+				lines = append(lines, line)
+			}
+			lines = append(lines, strings.Repeat(" ", colWidth))
+		}
+		cells[c] = lines
+		if len(lines) > height {
+			height = len(lines)
+		}
+	}
+
+	rows := make([]string, height)
+	for r := range rows {
+		var parts []string
+		for c := range columns {
+			cell := strings.Repeat(" ", colWidth)
+			if r < len(cells[c]) {
+				cell = cells[c][r]
+			}
+			parts = append(parts, cell)
+		}
+		rows[r] = strings.TrimRight(strings.Join(parts, strings.Repeat(" ", gap)), " ")
+	}
+	return rows
+}
+
+// fit truncates or pads s to exactly width characters
+func fit(s string, width int) string {
+	n := utf8.RuneCountInString(s)
+	if n > width {
+		return string([]rune(s)[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+// runBoard is the interactive board: the selected card moves between
+// columns with moveWorkItem, as kira move does
+func runBoard(cfg *config.Config, filter itemFilter) error {
+	if !prompts.tty {
+		return fmt.Errorf("the interactive board needs a terminal")
+	}
+	restore, err := rawMode()
+	if err != nil {
+		return err
+	}
+	defer restore()
+	fmt.Fprint(prompts.out, "\x1b[?1049h\x1b[?25l") // alternate screen, hidden cursor
+	defer fmt.Fprint(prompts.out, "\x1b[?25h\x1b[?1049l")
+
+	col, row := 0, 0
+	follow, message := "", ""
+	for {
+		columns, err := buildBoard(cfg, filter)
+		if err != nil {
+			return err
+		}
+		if follow != "" {
+			for c, column := range columns {
+				for r, item := range column.Items {
+					if item.ID == follow {
+						col, row = c, r
+					}
+				}
+			}
+			follow = ""
+		}
+		col = clamp(col, 0, len(columns)-1)
+		row = clamp(row, 0, len(columns[col].Items)-1)
+
+		lines := renderBoard(columns, terminalWidth(), col, row)
+		lines = append(lines, "", "←/→ column  ↑/↓ card  </> move card  q quit", message)
+		fmt.Fprint(prompts.out, "\x1b[H\x1b[2J"+strings.Join(lines, "\r\n"))
+		message = ""
+
+		key, err := readBoardKey(prompts.in)
+		if err != nil {
+			return err
+		}
+		move := 0
+		switch key {
+		case "quit":
+			return nil
+		case "left":
+			col--
+		case "right":
+			col++
+		case "up":
+			row--
+		case "down":
+			row++
+		case "move-left":
+			move = -1
+		case "move-right":
+			move = 1
+		}
+		target := col + move
+		if move == 0 || len(columns[col].Items) == 0 || target < 0 || target >= len(columns) {
+			continue
+		}
+		item := columns[col].Items[row]
+		// moveWorkItem's own message is cleared by the next redraw
+		if err := moveWorkItem(cfg, os.Stdout, item.ID, columns[target].Status); err != nil {
+			message = err.Error()
+			continue
+		}
+		message = fmt.Sprintf("Moved %s to %s", item.ID, columns[target].Status)
+		follow = item.ID
+	}
+}
+
+// readBoardKey reads one key press and names it. Terminals send an escape
+// sequence in one write, so an ESC with nothing buffered after it is the Esc
+// key itself, which quits.
+func readBoardKey(in *bufio.Reader) (string, error) {
+	b, err := in.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	switch b {
+	case 3, 'q':
+		return "quit", nil
+	case 'h':
+		return "left", nil
+	case 'l':
+		return "right", nil
+	case 'k':
+		return "up", nil
+	case 'j':
+		return "down", nil
+	case '<', 'H':
+		return "move-left", nil
+	case '>', 'L':
+		return "move-right", nil
+	case 27:
+		if in.Buffered() == 0 {
+			return "quit", nil
+		}
+		// Arrows are ESC [ A-D; with Shift, ESC [ 1 ; 2 A-D
+		var seq []byte
+		for len(seq) < 5 && in.Buffered() > 0 {
+			c, err := in.ReadByte()
+			if err != nil {
+				return "", err
+			}
+			seq = append(seq, c)
+			if c >= 'A' && c <= 'Z' || c == '~' {
+				break
+			}
+		}
+		shift := strings.Contains(string(seq), ";2")
+		switch seq[len(seq)-1] {
+		case 'A':
+			return "up", nil
+		case 'B':
+			return "down", nil
+		case 'C':
+			if shift {
+				return "move-right", nil
+			}
+			return "right", nil
+		case 'D':
+			if shift {
+				return "move-left", nil
+			}
+			return "left", nil
+		}
+	}
+	return "", nil
+}
+
+func clamp(n, lo, hi int) int {
+	if n > hi {
+		n = hi
+	}
+	if n < lo {
+		n = lo
+	}
+	return n
+}
@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"bufio"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestBuildBoard(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer os.Chdir("/")
+
+	cfg := &config.Config{StatusFolders: map[string]string{
+		"todo":     "1_todo",
+		"doing":    "2_doing",
+		"done":     "10_done",
+		"archived": "z_archive",
+	}, Board: config.BoardConfig{WIPLimits: map[string]int{"doing": 3}}}
+	write := func(folder, name, frontMatter string) {
+		require.NoError(t, os.MkdirAll(".work/"+folder, 0755))
+		require.NoError(t, os.WriteFile(".work/"+folder+"/"+name, []byte("---\n"+frontMatter+"---\n"), 0644))
+	}
+	write("1_todo", "002-b.task.md", "id: 002\ntitle: B\nkind: task\ntags: [ui]\n")
+	write("1_todo", "001-a.issue.md", "id: 001\ntitle: A\nkind: issue\nassigned: alice\n")
+	write("2_doing", "003-c.task.md", "id: 003\ntitle: C\nkind: task\n")
+	write("10_done/v2", "004-d.task.md", "id: 004\ntitle: D\nkind: task\nassigned: bob\n")
+	write("z_archive/2024-01-01", "005-e.task.md", "id: 005\ntitle: E\nkind: task\n")
+
+	columns, err := buildBoard(cfg, itemFilter{})
+	require.NoError(t, err)
+	var statuses []string
+	for _, c := range columns {
+		statuses = append(statuses, c.Status)
+	}
+	assert.Equal(t, []string{"todo", "doing", "done"}, statuses, "ordered by numeric folder prefix, without the archive")
+	require.Len(t, columns[0].Items, 2)
+	assert.Equal(t, "001", columns[0].Items[0].ID)
+	assert.Equal(t, []string{"ui"}, columns[0].Items[1].Tags)
+	assert.Equal(t, 3, columns[1].Limit, "from board.wip_limits")
+	assert.Equal(t, 0, columns[0].Limit, "unlimited by default")
+	require.Len(t, columns[2].Items, 1, "items in subfolders are included")
+	assert.Equal(t, "004", columns[2].Items[0].ID)
+
+	columns, err = buildBoard(cfg, itemFilter{Assigned: "@Alice"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, columns[0].Count, "counts ignore filters")
+	require.Len(t, columns[0].Items, 1)
+	assert.Equal(t, "001", columns[0].Items[0].ID)
+	assert.Empty(t, columns[2].Items)
+
+	columns, err = buildBoard(cfg, itemFilter{Kind: "task", Tag: "#ui"})
+	require.NoError(t, err)
+	require.Len(t, columns[0].Items, 1)
+	assert.Equal(t, "002", columns[0].Items[0].ID)
+
+	lines := renderBoard(columns, 60, -1, -1)
+	assert.True(t, strings.HasPrefix(lines[0], "TODO (2)"))
+	assert.Contains(t, lines[0], "DOING (1/3)")
+	assert.Contains(t, lines[2], "002 B")
+	assert.Contains(t, lines[3], "#ui")
+}
+
+func TestReadBoardKey(t *testing.T) {
+	in := bufio.NewReader(strings.NewReader("l\x1b[B\x1b[1;2D>q"))
+	var keys []string
+	for i := 0; i < 5; i++ {
+		key, err := readBoardKey(in)
+		require.NoError(t, err)
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []string{"right", "down", "move-left", "move-right", "quit"}, keys)
+
+	key, err := readBoardKey(bufio.NewReader(strings.NewReader("\x1b")))
+	require.NoError(t, err)
+	assert.Equal(t, "quit", key, "a lone Esc quits without waiting for more keys")
+}
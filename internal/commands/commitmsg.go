@@ -0,0 +1,213 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+	"kira/internal/archive"
+	"kira/internal/config"
+)
+
+// emptyTree is git's empty tree, the base of a repository's first commit
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// itemsTrailer lists the IDs of the work items a save commit touches
+const itemsTrailer = "Kira-Items"
+
+// maxSubjectEvents is how many events fit in a subject line before the rest
+// move to the body
+const maxSubjectEvents = 3
+
+// commitSummary describes the staged work item changes. It is the data of
+// the commit.message_template setting.
+type commitSummary struct {
+	Type    string   // Conventional Commits type
+	Scope   string   // kira, or release when items are released
+	Summary string   // events for the subject line
+	Events  []string // every event, e.g. "move 012 todo→doing"
+	Items   []string // IDs of the items involved, sorted
+}
+
+// describeChanges summarises the work item changes staged against base
+func describeChanges(cfg *config.Config, base string) (commitSummary, error) {
+	summary := commitSummary{Type: "chore", Scope: "kira"}
+	out, err := runGit("diff", "--cached", "--relative", "--name-status", "-M", "-z", base, "--", ".work", config.ConfigPath())
+	if err != nil {
+		return summary, err
+	}
+
+	type entry struct{ status, from, path string }
+	var entries []entry
+	fields := strings.Split(strings.TrimSuffix(out, "\x00"), "\x00")
+	for i := 0; i+1 < len(fields); i += 2 {
+		e := entry{status: fields[i][:1], from: fields[i+1], path: fields[i+1]}
+		if e.status == "R" || e.status == "C" {
+			if i+2 >= len(fields) {
+				break
+			}
+			e.path = fields[i+2]
+			i++
+		}
+		entries = append(entries, e)
+	}
+
+	items := make(map[string]bool)
+	batched := make(map[string]bool)
+	archiveRoot := filepath.ToSlash(filepath.Join(".work", cfg.StatusFolders["archived"])) + "/"
+
+	// Releases and abandonments first: their items are reported as one event
+	for _, e := range entries {
+		if e.status != "A" || filepath.Base(e.path) != archive.ManifestFile || !strings.HasPrefix(e.path, archiveRoot) {
+			continue
+		}
+		content, err := runGit("show", ":./"+e.path)
+		if err != nil {
+			continue
+		}
+		var m archive.Manifest
+		if yaml.Unmarshal([]byte(content), &m) != nil {
+			continue
+		}
+		for _, item := range m.Items {
+			batched[filepath.ToSlash(item.Path)] = true
+			if item.ID != "" {
+				items[item.ID] = true
+			}
+		}
+		event := fmt.Sprintf("%s %d items", batchVerb(m.Type), len(m.Items))
+		if len(m.Items) == 1 {
+			event = fmt.Sprintf("%s %s", batchVerb(m.Type), m.Items[0].ID)
+		}
+		if m.Version != "" {
+			event += " " + m.Version
+		}
+		if m.Type == "release" {
+			summary.Scope = "release"
+		}
+		summary.Events = append(summary.Events, event)
+	}
+
+	var other []string
+	for _, e := range entries {
+		switch {
+		case batched[e.path] || filepath.Base(e.path) == archive.ManifestFile:
+			continue
+		case e.path == config.ConfigPath():
+			other = appendUnique(other, "update config")
+			continue
+		case strings.HasSuffix(e.path, "IDEAS.md"):
+			other = appendUnique(other, "update ideas")
+			continue
+		case strings.HasPrefix(e.path, ".work/templates/"):
+			other = appendUnique(other, "update templates")
+			continue
+		case !strings.HasSuffix(e.path, ".md"):
+			continue
+		}
+
+		var before, after string
+		if e.status != "A" {
+			before, _ = runGit("show", base+":./"+e.from)
+		}
+		if e.status != "D" {
+			after, _ = runGit("show", ":./"+e.path)
+		}
+		id := archive.FrontMatterField(after, "id")
+		if id == "" {
+			id = archive.FrontMatterField(before, "id")
+		}
+		if id == "" {
+			continue
+		}
+		items[id] = true
+
+		oldStatus := archive.FrontMatterField(before, "status")
+		newStatus := archive.FrontMatterField(after, "status")
+		switch {
+		case e.status == "A":
+			summary.Events = append(summary.Events, fmt.Sprintf("create %s (%s: %s)", id, archive.FrontMatterField(after, "kind"), archive.FrontMatterField(after, "title")))
+		case e.status == "D":
+			summary.Events = append(summary.Events, fmt.Sprintf("delete %s", id))
+		case strings.HasPrefix(e.path, archiveRoot) && !strings.HasPrefix(e.from, archiveRoot):
+			summary.Events = append(summary.Events, fmt.Sprintf("archive %s", id))
+		case oldStatus != newStatus:
+			summary.Events = append(summary.Events, fmt.Sprintf("move %s %s→%s", id, oldStatus, newStatus))
+		default:
+			summary.Events = append(summary.Events, fmt.Sprintf("update %s", id))
+		}
+	}
+	summary.Events = append(summary.Events, other...)
+
+	for id := range items {
+		summary.Items = append(summary.Items, id)
+	}
+	sort.Strings(summary.Items)
+
+	subject := summary.Events
+	if len(subject) > maxSubjectEvents+1 {
+		subject = append(append([]string{}, subject[:maxSubjectEvents]...), fmt.Sprintf("and %d more", len(summary.Events)-maxSubjectEvents))
+	}
+	summary.Summary = strings.Join(subject, "; ")
+	return summary, nil
+}
+
+func batchVerb(batchType string) string {
+	switch batchType {
+	case "release":
+		return "release"
+	case "abandon":
+		return "abandon"
+	}
+	return "archive"
+}
+
+func appendUnique(list []string, s string) []string {
+	if containsString(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+// buildCommitMessage returns the message for a save commit: the given
+// message, or one generated from the changes with commit.message_template,
+// followed by a Kira-Items trailer
+func buildCommitMessage(cfg *config.Config, message string, summary commitSummary) (string, error) {
+	if message == "" && len(summary.Events) > 0 {
+		tmpl, err := template.New("commit").Parse(cfg.Commit.MessageTemplate)
+		if err != nil {
+			return "", fmt.Errorf("invalid commit.message_template: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, summary); err != nil {
+			return "", fmt.Errorf("invalid commit.message_template: %w", err)
+		}
+		message = strings.TrimSpace(buf.String())
+		if summary.Summary != strings.Join(summary.Events, "; ") {
+			message += "\n\n- " + strings.Join(summary.Events, "\n- ")
+		}
+	}
+	if message == "" {
+		message = cfg.Commit.DefaultMessage
+	}
+	if len(summary.Items) > 0 && !strings.Contains(message, itemsTrailer+":") {
+		message += fmt.Sprintf("\n\n%s: %s", itemsTrailer, strings.Join(summary.Items, ","))
+	}
+	return message, nil
+}
+
+// commitBase is the revision the staged changes of a save are compared with
+func commitBase(amend bool) string {
+	base := "HEAD"
+	if amend {
+		base = "HEAD^"
+	}
+	if _, err := runGit("rev-parse", "--verify", "--quiet", base); err != nil {
+		return emptyTree
+	}
+	return base
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// runGit runs a git command and returns its stdout. On failure the error
+// carries git's own stderr so it can be shown to the user verbatim.
+func runGit(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		name := args[0]
+		if name == "-C" && len(args) > 2 {
+			name = args[2]
+		}
+		return stdout.String(), fmt.Errorf("git %s: %s", name, msg)
+	}
+	return stdout.String(), nil
+}
+
+// isGitRepo reports whether the working directory is inside a git work tree
+func isGitRepo() bool {
+	out, err := runGit("rev-parse", "--is-inside-work-tree")
+	return err == nil && strings.TrimSpace(out) == "true"
+}
+
+// isGitRepoRoot reports whether dir is a git repository itself, bare or the
+// top of a work tree, rather than a folder inside one
+func isGitRepoRoot(dir string) bool {
+	if out, err := runGit("-C", dir, "rev-parse", "--is-bare-repository"); err != nil {
+		return false
+	} else if strings.TrimSpace(out) == "true" {
+		return true
+	}
+	out, err := runGit("-C", dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return false
+	}
+	top, err := filepath.EvalSymlinks(strings.TrimSpace(out))
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	if abs, err = filepath.EvalSymlinks(abs); err != nil {
+		return false
+	}
+	return top == abs
+}
+
+// isTrackedByGit reports whether path is in the git index
+func isTrackedByGit(path string) bool {
+	_, err := runGit("ls-files", "--error-unmatch", "--", path)
+	return err == nil
+}
@@ -0,0 +1,330 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"kira/internal/config"
+	"kira/internal/validation"
+)
+
+// kiraHooks are the git hooks installed by kira git install-hooks
+var kiraHooks = []string{"pre-commit", "commit-msg", "post-commit"}
+
+// hookMarker identifies a hook script written by kira
+const hookMarker = "# Installed by kira git install-hooks"
+
+// chainedHookSuffix is appended to the name of a hook kira replaced; kira's
+// hook runs it first
+const chainedHookSuffix = ".pre-kira"
+
+var gitInstallHooksCmd = &cobra.Command{
+	Use:   "install-hooks",
+	Short: "Install git hooks that lint work items and check item references",
+	Long: `Installs three git hooks:
+
+  pre-commit   validates the work items staged for the commit
+  commit-msg   checks that code commits reference a work item (Refs: #012);
+               hooks.commit_refs is off, warn or require
+  post-commit  moves items closed by the commit, e.g. 'Fixes #012' moves 012
+               to review (hooks.transitions)
+
+Existing hooks are kept as <hook>.pre-kira and run before kira's.
+Safe to run more than once.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		return installHooks()
+	},
+}
+
+var gitHookCmd = &cobra.Command{
+	Use:    "hook <name> [args]",
+	Short:  "Run one of kira's git hooks (run by git)",
+	Hidden: true,
+	Args:   cobra.MinimumNArgs(1),
+	// Hook failures are reported by git; usage text would only add noise
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if checkWorkDir() != nil {
+			return nil // not a kira workspace: nothing to check
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		switch args[0] {
+		case "pre-commit":
+			return runPreCommitHook(cfg)
+		case "commit-msg":
+			if len(args) < 2 {
+				return fmt.Errorf("commit-msg hook needs the message file")
+			}
+			return runCommitMsgHook(cfg, args[1])
+		case "post-commit":
+			return runPostCommitHook(cfg)
+		}
+		return fmt.Errorf("unknown hook: %s", args[0])
+	},
+}
+
+func init() {
+	gitCmd.AddCommand(gitInstallHooksCmd)
+	gitCmd.AddCommand(gitHookCmd)
+}
+
+// hookScript runs any chained hook, then kira at exe. Git runs hooks without
+// kira's PATH from GUI clients and CI, so the binary is called by its path,
+// and a missing binary fails loudly rather than skipping the checks.
+func hookScript(name, exe string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s
+hook_dir=$(dirname "$0")
+if [ -x "$hook_dir/%[2]s%[3]s" ]; then
+	"$hook_dir/%[2]s%[3]s" "$@" || exit $?
+fi
+kira=%[4]s
+if [ ! -x "$kira" ]; then
+	echo "kira %[2]s hook: $kira not found; run 'kira git install-hooks' again or skip with --no-verify" >&2
+	exit 1
+fi
+exec "$kira" git hook %[2]s "$@"
+`, hookMarker, name, chainedHookSuffix, shellQuote(exe))
+}
+
+func installHooks() error {
+	if !isGitRepo() {
+		return fmt.Errorf("not a git repository: run 'git init' first")
+	}
+	out, err := runGit("rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return fmt.Errorf("failed to find hooks directory: %w", err)
+	}
+	dir := strings.TrimSpace(out)
+	exe, err := kiraExecutable()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create hooks directory: %w", err)
+	}
+
+	for _, name := range kiraHooks {
+		path := filepath.Join(dir, name)
+		chained := path + chainedHookSuffix
+		existing, err := os.ReadFile(path)
+		switch {
+		case os.IsNotExist(err):
+		case err != nil:
+			return fmt.Errorf("failed to read %s hook: %w", name, err)
+		case !strings.Contains(string(existing), hookMarker):
+			if _, err := os.Stat(chained); err == nil {
+				return fmt.Errorf("cannot chain the existing %s hook: %s already exists", name, chained)
+			}
+			if err := os.Rename(path, chained); err != nil {
+				return fmt.Errorf("failed to keep the existing %s hook: %w", name, err)
+			}
+			fmt.Printf("Kept the existing %s hook as %s; it runs first.\n", name, filepath.Base(chained))
+		}
+		if err := os.WriteFile(path, []byte(hookScript(name, exe)), 0755); err != nil {
+			return fmt.Errorf("failed to write %s hook: %w", name, err)
+		}
+		fmt.Printf("Installed %s hook\n", name)
+	}
+	return nil
+}
+
+// runPreCommitHook validates the staged work items, as they will be
+// committed rather than as they are in the working tree
+func runPreCommitHook(cfg *config.Config) error {
+	out, err := runGit("diff", "--cached", "--name-only", "--relative", "--diff-filter=ACMR", "-z", "--", ".work")
+	if err != nil {
+		return err
+	}
+	staged := make(map[string][]byte)
+	for _, f := range strings.Split(out, "\x00") {
+		if !strings.HasSuffix(f, ".md") {
+			continue
+		}
+		content, err := runGit("show", ":./"+f)
+		if err != nil {
+			return fmt.Errorf("failed to read staged %s: %w", f, err)
+		}
+		staged[f] = []byte(content)
+	}
+	if len(staged) == 0 {
+		return nil
+	}
+
+	result, err := validation.ValidateWorkItemContents(cfg, staged)
+	if err != nil {
+		return fmt.Errorf("failed to validate work items: %w", err)
+	}
+	if result.HasErrors() {
+		fmt.Fprintln(os.Stderr, "Validation errors in staged work items:")
+		for _, e := range result.Errors {
+			fmt.Fprintf(os.Stderr, "  %s\n", e.Error())
+		}
+		return fmt.Errorf("validation failed (commit with --no-verify to skip)")
+	}
+	return nil
+}
+
+// itemRef is a work item referenced from a commit message, such as
+// "Refs: #012" or "Fixes #012"
+type itemRef struct {
+	Keyword string // refs, fixes, closes or resolves
+	ID      string
+}
+
+var (
+	itemRefRe   = regexp.MustCompile(`(?i)\b(refs?|fix(?:es|ed)?|close[sd]?|resolve[sd]?)\b:?[ \t]*((?:#[\w-]+(?:[ \t]*,[ \t]*|[ \t]+and[ \t]+|[ \t]+)?)+)`)
+	itemRefIDRe = regexp.MustCompile(`#([\w-]+)`)
+)
+
+// itemRefs finds the work item references in a commit message. IDs must
+// match validation.id_format.
+func itemRefs(cfg *config.Config, message string) []itemRef {
+	idFormat, err := regexp.Compile(cfg.Validation.IDFormat)
+	if err != nil {
+		return nil
+	}
+	var refs []itemRef
+	seen := make(map[itemRef]bool)
+	for _, m := range itemRefRe.FindAllStringSubmatch(message, -1) {
+		keyword := strings.ToLower(m[1])
+		switch {
+		case strings.HasPrefix(keyword, "ref"):
+			keyword = "refs"
+		case strings.HasPrefix(keyword, "fix"):
+			keyword = "fixes"
+		case strings.HasPrefix(keyword, "close"):
+			keyword = "closes"
+		default:
+			keyword = "resolves"
+		}
+		for _, id := range itemRefIDRe.FindAllStringSubmatch(m[2], -1) {
+			ref := itemRef{Keyword: keyword, ID: id[1]}
+			if idFormat.MatchString(ref.ID) && !seen[ref] {
+				seen[ref] = true
+				refs = append(refs, ref)
+			}
+		}
+	}
+	return refs
+}
+
+// commitMessageText drops the comment lines git adds to a message being edited
+func commitMessageText(content string) string {
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(line, "# ------------------------ >8") {
+			break
+		}
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// exemptFromItemRefs reports whether a message is from kira save or git
+// itself, which need no work item reference
+func exemptFromItemRefs(message string) bool {
+	for _, prefix := range []string{"Merge ", "Revert ", "fixup! ", "squash! ", "amend! "} {
+		if strings.HasPrefix(message, prefix) {
+			return true
+		}
+	}
+	return strings.Contains(message, "\n"+itemsTrailer+":")
+}
+
+// runCommitMsgHook checks that a code commit references a work item
+func runCommitMsgHook(cfg *config.Config, messageFile string) error {
+	mode := cfg.Hooks.CommitRefs
+	if mode == "off" {
+		return nil
+	}
+	content, err := os.ReadFile(messageFile)
+	if err != nil {
+		return fmt.Errorf("failed to read commit message: %w", err)
+	}
+	message := commitMessageText(string(content))
+	if message == "" || exemptFromItemRefs(message) {
+		return nil
+	}
+
+	// Commits touching only kira's files are work item bookkeeping
+	out, err := runGit("diff", "--cached", "--name-only", "--relative", "-z")
+	if err != nil {
+		return err
+	}
+	code := false
+	for _, f := range strings.Split(out, "\x00") {
+		if f != "" && !strings.HasPrefix(f, ".work/") && f != config.ConfigPath() && f != cfg.Release.ReleasesFile {
+			code = true
+			break
+		}
+	}
+	if !code {
+		return nil
+	}
+
+	var problems []string
+	refs := itemRefs(cfg, message)
+	if len(refs) == 0 {
+		problems = append(problems, "the commit message references no work item (add e.g. 'Refs: #012')")
+	}
+	for _, ref := range refs {
+		if _, err := findWorkItemFile(ref.ID); err != nil {
+			problems = append(problems, fmt.Sprintf("the commit message references unknown work item #%s", ref.ID))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	if mode == "require" {
+		return fmt.Errorf("%s (hooks.commit_refs is require; commit with --no-verify to skip)", strings.Join(problems, "; "))
+	}
+	for _, p := range problems {
+		fmt.Fprintf(os.Stderr, "kira: warning: %s\n", p)
+	}
+	return nil
+}
+
+// runPostCommitHook moves the items the last commit closes according to
+// hooks.transitions. Items already at or past the target status stay put.
+func runPostCommitHook(cfg *config.Config) error {
+	message, err := runGit("log", "-1", "--format=%B")
+	if err != nil {
+		return err
+	}
+	if exemptFromItemRefs(strings.TrimSpace(message)) {
+		return nil
+	}
+
+	for _, ref := range itemRefs(cfg, message) {
+		status := cfg.Hooks.Transitions[ref.Keyword]
+		if _, ok := cfg.StatusFolders[status]; !ok {
+			continue
+		}
+		path, err := findWorkItemFile(ref.ID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "kira: %v\n", err)
+			continue
+		}
+		if reachedStatus(cfg, path, status) {
+			continue
+		}
+		if err := moveWorkItem(cfg, os.Stdout, ref.ID, status); err != nil {
+			fmt.Fprintf(os.Stderr, "kira: %v\n", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestItemRefs(t *testing.T) {
+	cfg := &config.DefaultConfig
+	refs := itemRefs(cfg, "Fix login redirect\n\nFixes #012, #013 and closes #014.\nRefs: #015 #abc\nSee issue #016")
+	assert.Equal(t, []itemRef{
+		{Keyword: "fixes", ID: "012"},
+		{Keyword: "fixes", ID: "013"},
+		{Keyword: "closes", ID: "014"},
+		{Keyword: "refs", ID: "015"},
+	}, refs)
+}
+
+func TestInstallHooksChainsExisting(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+
+	existing := "#!/bin/sh\necho lint\n"
+	require.NoError(t, os.WriteFile(".git/hooks/pre-commit", []byte(existing), 0755))
+	require.NoError(t, installHooks())
+	require.NoError(t, installHooks())
+
+	chained, err := os.ReadFile(".git/hooks/pre-commit" + chainedHookSuffix)
+	require.NoError(t, err)
+	assert.Equal(t, existing, string(chained))
+	for _, name := range kiraHooks {
+		script, err := os.ReadFile(filepath.Join(".git", "hooks", name))
+		require.NoError(t, err)
+		exe, err := kiraExecutable()
+		require.NoError(t, err)
+		assert.Equal(t, hookScript(name, exe), string(script))
+	}
+
+	// A hook whose kira binary is gone fails instead of skipping its checks
+	require.NoError(t, os.WriteFile(".git/hooks/pre-commit", []byte(hookScript("pre-commit", "/nonexistent/kira")), 0755))
+	out, err := exec.Command(".git/hooks/pre-commit").CombinedOutput()
+	assert.Error(t, err)
+	assert.Contains(t, string(out), "/nonexistent/kira not found")
+}
+
+func TestCommitHooks(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	item := "---\nid: 001\ntitle: Login\nstatus: todo\nkind: task\ncreated: 2024-01-01\n---\n# Login\n"
+	require.NoError(t, os.WriteFile(".work/1_todo/001-login.task.md", []byte(item), 0644))
+	require.NoError(t, os.WriteFile(".work/1_todo/002-broken.task.md", []byte("---\nid: 2\n---\n"), 0644))
+
+	// pre-commit checks only staged items
+	_, err = runGit("add", ".work/1_todo/001-login.task.md")
+	require.NoError(t, err)
+	require.NoError(t, runPreCommitHook(cfg))
+	_, err = runGit("add", ".work/1_todo/002-broken.task.md")
+	require.NoError(t, err)
+	assert.Error(t, runPreCommitHook(cfg))
+	_, err = runGit("rm", "-q", "--cached", ".work/1_todo/002-broken.task.md")
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(".work/1_todo/002-broken.task.md"))
+
+	// pre-commit checks the staged content, not the working tree
+	require.NoError(t, os.WriteFile(".work/1_todo/001-login.task.md", []byte("---\nid: 1\n---\n"), 0644))
+	require.NoError(t, runPreCommitHook(cfg), "unstaged breakage is not committed")
+	_, err = runGit("add", ".work/1_todo/001-login.task.md")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(".work/1_todo/001-login.task.md", []byte(item), 0644))
+	assert.Error(t, runPreCommitHook(cfg), "a fix left unstaged does not help")
+	_, err = runGit("add", ".work/1_todo/001-login.task.md")
+	require.NoError(t, err)
+	require.NoError(t, runPreCommitHook(cfg))
+
+	// commit-msg requires a reference on code commits
+	require.NoError(t, os.WriteFile("main.go", []byte("package main\n"), 0644))
+	_, err = runGit("add", "main.go")
+	require.NoError(t, err)
+	msg := filepath.Join(t.TempDir(), "COMMIT_EDITMSG")
+	require.NoError(t, os.WriteFile(msg, []byte("Add login\n# Please enter the commit message\n"), 0644))
+	require.NoError(t, runCommitMsgHook(cfg, msg), "warn mode only warns")
+	cfg.Hooks.CommitRefs = "require"
+	assert.Error(t, runCommitMsgHook(cfg, msg))
+	require.NoError(t, os.WriteFile(msg, []byte("Add login\n\nRefs: #009\n"), 0644))
+	assert.ErrorContains(t, runCommitMsgHook(cfg, msg), "unknown work item #009")
+	require.NoError(t, os.WriteFile(msg, []byte("Add login\n\nFixes #001\n"), 0644))
+	require.NoError(t, runCommitMsgHook(cfg, msg))
+
+	// post-commit moves the fixed item to review
+	_, err = runGit("commit", "-q", "-m", "Add login\n\nFixes #001")
+	require.NoError(t, err)
+	require.NoError(t, runPostCommitHook(cfg))
+	assert.FileExists(t, ".work/3_review/001-login.task.md")
+	require.NoError(t, runPostCommitHook(cfg), "items already in review stay put")
+	assert.FileExists(t, ".work/3_review/001-login.task.md")
+}
@@ -4,27 +4,112 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"kira/internal/config"
+	"kira/internal/templates"
 )
 
+const ideaTimeFormat = "2006-01-02 15:04:05"
+
 var ideaCmd = &cobra.Command{
 	Use:   "idea <description>",
 	Short: "Add an idea to IDEAS.md",
-	Long:  `Adds an idea with a timestamp to the IDEAS.md file.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Adds an idea with a timestamp to the IDEAS.md file.
+
+Ideas are listed with 'kira idea list', turned into work items with
+'kira idea promote' and discarded with 'kira idea drop'.`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := checkWorkDir(); err != nil {
 			return err
 		}
 
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		author, _ := cmd.Flags().GetString("author")
 		description := args[0]
-		return addIdea(description)
+		return addIdea(description, tags, author)
+	},
+}
+
+var ideaListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List ideas with their number and age",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		tags, _ := cmd.Flags().GetStringSlice("tag")
+		return listIdeas(tags)
+	},
+}
+
+var ideaPromoteCmd = &cobra.Command{
+	Use:   "promote <n>",
+	Short: "Turn an idea into a work item",
+	Long: `Creates a work item of the given kind from idea number n (see 'kira idea list').
+The idea becomes the title and fills the template's first free-text section.
+The bullet in IDEAS.md is struck through with a reference to the new item,
+or removed with --remove.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid idea number '%s'", args[0])
+		}
+		kind, _ := cmd.Flags().GetString("kind")
+		title, _ := cmd.Flags().GetString("title")
+		status, _ := cmd.Flags().GetString("status")
+		inputs, _ := cmd.Flags().GetStringToString("input")
+		remove, _ := cmd.Flags().GetBool("remove")
+		return promoteIdea(cfg, n, itemSpec{Kind: kind, Title: title, Status: status, Inputs: toSpecInputs(inputs)}, remove)
+	},
+}
+
+var ideaDropCmd = &cobra.Command{
+	Use:   "drop <n>",
+	Short: "Remove an idea",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid idea number '%s'", args[0])
+		}
+		return dropIdea(n)
 	},
 }
 
-func addIdea(description string) error {
+func init() {
+	ideaCmd.Flags().StringSlice("tag", nil, "Tag the idea (repeatable)")
+	ideaCmd.Flags().String("author", "", "Record who had the idea")
+	ideaListCmd.Flags().StringSlice("tag", nil, "Only list ideas with this tag")
+	ideaPromoteCmd.Flags().String("kind", "", "Kind of work item to create (required)")
+	ideaPromoteCmd.Flags().String("title", "", "Title of the work item (default: the idea text)")
+	ideaPromoteCmd.Flags().String("status", "", "Status of the work item (default: the default status)")
+	ideaPromoteCmd.Flags().StringToStringP("input", "i", nil, "Provide template input values (e.g., --input estimate=3)")
+	ideaPromoteCmd.Flags().Bool("remove", false, "Remove the idea instead of striking it through")
+	ideaPromoteCmd.MarkFlagRequired("kind")
+	ideaCmd.AddCommand(ideaListCmd)
+	ideaCmd.AddCommand(ideaPromoteCmd)
+	ideaCmd.AddCommand(ideaDropCmd)
+}
+
+func addIdea(description string, tags []string, author string) error {
 	ideasPath := filepath.Join(".work", "IDEAS.md")
 
 	// Read existing content
@@ -34,8 +119,8 @@ func addIdea(description string) error {
 	}
 
 	// Append new idea with timestamp (UTC for deterministic tests)
-		timestamp := time.Now().UTC().Format("2006-01-02 15:04:05")
-		newIdea := fmt.Sprintf("- [%s] %s\n", timestamp, description)
+		timestamp := time.Now().UTC().Format(ideaTimeFormat)
+		newIdea := fmt.Sprintf("- [%s] %s\n", timestamp, formatIdeaText(description, tags, author))
 
 	// Append to content
 	newContent := string(content) + newIdea
@@ -49,3 +134,268 @@ func addIdea(description string) error {
 	return nil
 }
 
+// idea is one open bullet in IDEAS.md:
+//
+//	- [2025-01-02 15:04:05] Dark mode #ui #design (by alice)
+type idea struct {
+	Line   int // index of the bullet in the file's lines
+	Time   time.Time
+	Text   string
+	Tags   []string
+	Author string
+}
+
+var (
+	ideaLineRe   = regexp.MustCompile(`^- \[(\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2})\] (.*)$`)
+	ideaAuthorRe = regexp.MustCompile(`\s*\(by ([^()]+)\)$`)
+	ideaTagsRe   = regexp.MustCompile(`(\s+#[\w-]+)+$`)
+)
+
+func formatIdeaText(description string, tags []string, author string) string {
+	text := strings.TrimSpace(description)
+	for _, tag := range tags {
+		if tag = strings.TrimPrefix(strings.TrimSpace(tag), "#"); tag != "" {
+			text += " #" + tag
+		}
+	}
+	if author = strings.TrimSpace(author); author != "" {
+		text += fmt.Sprintf(" (by %s)", author)
+	}
+	return text
+}
+
+// parseIdea reads an open idea bullet; struck-through ideas do not match
+func parseIdea(line string) (idea, bool) {
+	m := ideaLineRe.FindStringSubmatch(line)
+	if m == nil {
+		return idea{}, false
+	}
+	ts, err := time.Parse(ideaTimeFormat, m[1])
+	if err != nil {
+		return idea{}, false
+	}
+	item := idea{Time: ts, Text: m[2]}
+	if am := ideaAuthorRe.FindStringSubmatch(item.Text); am != nil {
+		item.Author = am[1]
+		item.Text = strings.TrimSuffix(item.Text, am[0])
+	}
+	if tm := ideaTagsRe.FindString(item.Text); tm != "" {
+		for _, tag := range strings.Fields(tm) {
+			item.Tags = append(item.Tags, strings.TrimPrefix(tag, "#"))
+		}
+		item.Text = strings.TrimSuffix(item.Text, tm)
+	}
+	item.Text = strings.TrimSpace(item.Text)
+	return item, true
+}
+
+// readIdeas returns the lines of IDEAS.md and its open ideas in file order
+func readIdeas() ([]string, []idea, error) {
+	content, err := os.ReadFile(filepath.Join(".work", "IDEAS.md"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read IDEAS.md: %w", err)
+	}
+	lines := strings.Split(string(content), "\n")
+	var ideas []idea
+	for i, line := range lines {
+		if item, ok := parseIdea(line); ok {
+			item.Line = i
+			ideas = append(ideas, item)
+		}
+	}
+	return lines, ideas, nil
+}
+
+func writeIdeas(lines []string) error {
+	if err := os.WriteFile(filepath.Join(".work", "IDEAS.md"), []byte(strings.Join(lines, "\n")), 0644); err != nil {
+		return fmt.Errorf("failed to write IDEAS.md: %w", err)
+	}
+	return nil
+}
+
+// ideaAt returns idea number n, counting from 1
+func ideaAt(ideas []idea, n int) (idea, error) {
+	if n < 1 || n > len(ideas) {
+		return idea{}, fmt.Errorf("no idea number %d (there are %d)", n, len(ideas))
+	}
+	return ideas[n-1], nil
+}
+
+func listIdeas(tags []string) error {
+	_, ideas, err := readIdeas()
+	if err != nil {
+		return err
+	}
+	if len(ideas) == 0 {
+		fmt.Println("No ideas yet. Add one with 'kira idea \"...\"'.")
+		return nil
+	}
+	now := time.Now().UTC()
+	for i, item := range ideas {
+		if !ideaHasTags(item, tags) {
+			continue
+		}
+		line := fmt.Sprintf("%d. %s (%s)", i+1, item.Text, ideaAge(now.Sub(item.Time)))
+		for _, tag := range item.Tags {
+			line += " #" + tag
+		}
+		if item.Author != "" {
+			line += " by " + item.Author
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+func ideaHasTags(item idea, tags []string) bool {
+	for _, tag := range tags {
+		if !containsString(item.Tags, strings.TrimPrefix(tag, "#")) {
+			return false
+		}
+	}
+	return true
+}
+
+// ideaAge is a short human age such as "5m ago", "2d ago" or "4mo ago"
+func ideaAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 14*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 60*24*time.Hour:
+		return fmt.Sprintf("%dw ago", int(d.Hours()/24/7))
+	}
+	return fmt.Sprintf("%dmo ago", int(d.Hours()/24/30))
+}
+
+// promoteIdea creates a work item from idea n and strikes the idea through
+// with a reference to the item, or removes it
+func promoteIdea(cfg *config.Config, n int, spec itemSpec, remove bool) error {
+	lines, ideas, err := readIdeas()
+	if err != nil {
+		return err
+	}
+	item, err := ideaAt(ideas, n)
+	if err != nil {
+		return err
+	}
+
+	if spec.Title == "" {
+		spec.Title = item.Text
+	}
+	if spec.Inputs == nil {
+		spec.Inputs = make(map[string]interface{})
+	}
+	inputs, err := kindInputs(cfg, spec.Kind)
+	if err != nil {
+		return err
+	}
+	if field := firstTextInput(inputs); field != "" {
+		if _, given := spec.Inputs[field]; !given {
+			spec.Inputs[field] = item.Text
+		}
+	}
+	if _, given := spec.Inputs["tags"]; len(item.Tags) > 0 && !given {
+		tags, dropped := ideaTags(inputs, item.Tags)
+		if len(dropped) > 0 {
+			fmt.Printf("Left out tags the %s template does not offer: %s\n", spec.Kind, strings.Join(dropped, ", "))
+		}
+		if len(tags) > 0 {
+			spec.Inputs["tags"] = strings.Join(tags, ",")
+		}
+	}
+
+	planned, err := planItems(cfg, []itemSpec{spec})
+	if err != nil {
+		return err
+	}
+	if err := writeItems(planned); err != nil {
+		return err
+	}
+
+	if remove {
+		lines = append(lines[:item.Line], lines[item.Line+1:]...)
+	} else {
+		lines[item.Line] = fmt.Sprintf("- ~~%s~~ → %s", strings.TrimPrefix(lines[item.Line], "- "), planned[0].ID)
+	}
+	if err := writeIdeas(lines); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created work item %s in %s from idea %d\n", planned[0].ID, planned[0].Folder, n)
+	return nil
+}
+
+// kindInputs are the inputs of a kind's template
+func kindInputs(cfg *config.Config, kind string) ([]templates.Input, error) {
+	path, err := templatePath(cfg, kind)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := templates.LoadTemplate(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load template: %w", err)
+	}
+	return tmpl.Inputs, nil
+}
+
+// firstTextInput is the first free-text section among a template's inputs,
+// the one a promoted idea fills in
+func firstTextInput(inputs []templates.Input) string {
+	for _, input := range inputs {
+		if input.Multiline {
+			return input.Name
+		}
+	}
+	return ""
+}
+
+// ideaTags splits an idea's tags into those the template's tags input
+// accepts and those it does not offer
+func ideaTags(inputs []templates.Input, tags []string) (kept, dropped []string) {
+	for _, input := range inputs {
+		if input.Name != "tags" || len(input.Options) == 0 {
+			continue
+		}
+		for _, tag := range tags {
+			if containsString(input.Options, tag) {
+				kept = append(kept, tag)
+			} else {
+				dropped = append(dropped, tag)
+			}
+		}
+		return kept, dropped
+	}
+	return tags, nil
+}
+
+func dropIdea(n int) error {
+	lines, ideas, err := readIdeas()
+	if err != nil {
+		return err
+	}
+	item, err := ideaAt(ideas, n)
+	if err != nil {
+		return err
+	}
+	lines = append(lines[:item.Line], lines[item.Line+1:]...)
+	if err := writeIdeas(lines); err != nil {
+		return err
+	}
+	fmt.Printf("Dropped idea %d: %s\n", n, item.Text)
+	return nil
+}
+
+// toSpecInputs converts --input values for an itemSpec
+func toSpecInputs(values map[string]string) map[string]interface{} {
+	inputs := make(map[string]interface{})
+	for k, v := range values {
+		inputs[k] = v
+	}
+	return inputs
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"kira/internal/config"
 )
 
 func TestAddIdea(t *testing.T) {
@@ -26,7 +27,7 @@ func TestAddIdea(t *testing.T) {
 		os.WriteFile(".work/IDEAS.md", []byte(ideasContent), 0644)
 		
 		// Add an idea
-		err := addIdea("Test idea for testing")
+		err := addIdea("Test idea for testing", nil, "")
 		require.NoError(t, err)
 		
 		// Check that the idea was added
@@ -53,7 +54,7 @@ func TestAddIdea(t *testing.T) {
 		
 		// Add an idea
 		beforeTime := time.Now()
-		err := addIdea("Timestamped idea")
+		err := addIdea("Timestamped idea", nil, "")
 		require.NoError(t, err)
 		afterTime := time.Now()
 		
@@ -90,3 +91,47 @@ func TestAddIdea(t *testing.T) {
 		assert.True(t, timestamp.Before(afterTime.Add(time.Second)))
 	})
 }
+
+func TestPromoteAndDropIdeas(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, initializeWorkspace(tmpDir))
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+	cfg := &config.DefaultConfig
+
+	require.NoError(t, addIdea("Dark mode", []string{"maintenance", "#design"}, "alice"))
+	require.NoError(t, addIdea("Faster search", nil, ""))
+	require.NoError(t, addIdea("Export CSV", nil, ""))
+
+	_, ideas, err := readIdeas()
+	require.NoError(t, err)
+	require.Len(t, ideas, 3)
+	assert.Equal(t, "Dark mode", ideas[0].Text)
+	assert.Equal(t, []string{"maintenance", "design"}, ideas[0].Tags)
+	assert.Equal(t, "alice", ideas[0].Author)
+
+	require.NoError(t, promoteIdea(cfg, 1, itemSpec{Kind: "task"}, false))
+	content, err := os.ReadFile(".work/0_backlog/001-dark-mode.task.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "title: Dark mode")
+	assert.Contains(t, string(content), "## Description\nDark mode\n")
+	assert.Contains(t, string(content), "tags: [maintenance]\n", "tags the template offers carry over")
+
+	require.NoError(t, dropIdea(1))
+
+	lines, ideas, err := readIdeas()
+	require.NoError(t, err)
+	require.Len(t, ideas, 1)
+	assert.Equal(t, "Export CSV", ideas[0].Text)
+	assert.Contains(t, strings.Join(lines, "\n"), "(by alice)~~ → 001")
+	assert.NotContains(t, strings.Join(lines, "\n"), "Faster search")
+
+	assert.Error(t, dropIdea(2))
+}
+
+func TestIdeaAge(t *testing.T) {
+	assert.Equal(t, "just now", ideaAge(10*time.Second))
+	assert.Equal(t, "3h ago", ideaAge(3*time.Hour+5*time.Minute))
+	assert.Equal(t, "3w ago", ideaAge(22*24*time.Hour))
+	assert.Equal(t, "4mo ago", ideaAge(125*24*time.Hour))
+}
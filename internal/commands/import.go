@@ -0,0 +1,510 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"kira/internal/config"
+	"kira/internal/templates"
+	"kira/internal/validation"
+)
+
+var importCmd = &cobra.Command{
+	Use:   "import <batch.yaml>",
+	Short: "Create work items in bulk from a manifest",
+	Long: `Creates every work item listed in a YAML (or JSON) manifest:
+
+  items:
+    - ref: auth
+      kind: prd
+      title: User authentication
+      inputs:
+        tags: [security]
+    - kind: task
+      title: Login form
+      status: todo
+      body: |
+        Part of {{ref:auth}}.
+
+IDs are allocated in manifest order and {{ref:<name>}} anywhere in a title,
+input or body is replaced by the ID of the item with that ref. Nothing is
+written unless every item is valid.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		data, err := readSource(args[0])
+		if err != nil {
+			return err
+		}
+		specs, err := parseItemSpecs(data)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", args[0], err)
+		}
+		return createItems(cfg, specs, dryRun)
+	},
+}
+
+func init() {
+	importCmd.Flags().Bool("dry-run", false, "Show the items that would be created without writing them")
+}
+
+// itemSpec describes a work item created without prompting. Inputs are the
+// template inputs; list values may be given as sequences. Body, when set,
+// replaces everything the template renders below the front matter.
+type itemSpec struct {
+	Ref    string                 `yaml:"ref" json:"ref"`
+	Kind   string                 `yaml:"kind" json:"kind"`
+	Title  string                 `yaml:"title" json:"title"`
+	Status string                 `yaml:"status" json:"status"`
+	Inputs map[string]interface{} `yaml:"inputs" json:"inputs"`
+	Body   string                 `yaml:"body" json:"body"`
+}
+
+// plannedItem is a rendered work item waiting to be written
+type plannedItem struct {
+	ID      string
+	Path    string
+	Folder  string
+	Content string
+}
+
+var refRe = regexp.MustCompile(`\{\{ref:([A-Za-z0-9_.-]+)\}\}`)
+
+// readSource reads a file, or stdin for "-"
+func readSource(path string) ([]byte, error) {
+	if path == "-" {
+		data, err := io.ReadAll(prompts.in)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return data, nil
+}
+
+// parseItemSpecs accepts a single spec, a list of specs or a mapping with an
+// items list, in YAML or JSON
+func parseItemSpecs(data []byte) ([]itemSpec, error) {
+	var probe interface{}
+	if err := yaml.Unmarshal(data, &probe); err != nil {
+		return nil, err
+	}
+
+	var specs []itemSpec
+	switch v := probe.(type) {
+	case []interface{}:
+		if err := yaml.Unmarshal(data, &specs); err != nil {
+			return nil, err
+		}
+	case map[string]interface{}:
+		if _, ok := v["items"]; ok {
+			var manifest struct {
+				Items []itemSpec `yaml:"items"`
+			}
+			if err := yaml.Unmarshal(data, &manifest); err != nil {
+				return nil, err
+			}
+			specs = manifest.Items
+		} else {
+			var spec itemSpec
+			if err := yaml.Unmarshal(data, &spec); err != nil {
+				return nil, err
+			}
+			specs = []itemSpec{spec}
+		}
+	default:
+		return nil, fmt.Errorf("expected an item, a list of items or an items mapping")
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no items to create")
+	}
+	return specs, nil
+}
+
+// parseJSONItemSpecs reads one spec or an array of specs as JSON
+func parseJSONItemSpecs(data []byte) ([]itemSpec, error) {
+	trimmed := strings.TrimSpace(string(data))
+	if strings.HasPrefix(trimmed, "[") {
+		var specs []itemSpec
+		if err := json.Unmarshal(data, &specs); err != nil {
+			return nil, err
+		}
+		if len(specs) == 0 {
+			return nil, fmt.Errorf("no items to create")
+		}
+		return specs, nil
+	}
+	var spec itemSpec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	return []itemSpec{spec}, nil
+}
+
+// createItems renders every spec and writes them all, or none when any spec
+// is invalid or a write fails
+func createItems(cfg *config.Config, specs []itemSpec, dryRun bool) error {
+	items, err := planItems(cfg, specs)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		for _, item := range items {
+			fmt.Printf("Would create %s\n", item.Path)
+		}
+		return nil
+	}
+	if err := writeItems(items); err != nil {
+		return err
+	}
+	for _, item := range items {
+		fmt.Printf("Created work item %s in %s\n", item.ID, item.Folder)
+	}
+	return nil
+}
+
+// planItems allocates consecutive IDs, resolves references between the new
+// items and renders each one from its template
+func planItems(cfg *config.Config, specs []itemSpec) ([]plannedItem, error) {
+	first, err := validation.GetNextID()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get next ID: %w", err)
+	}
+	base, _ := strconv.Atoi(first)
+	idAt := func(i int) string { return fmt.Sprintf("%03d", base+i) }
+
+	refs := make(map[string]string)
+	for i, spec := range specs {
+		if spec.Ref == "" {
+			continue
+		}
+		if _, dup := refs[spec.Ref]; dup {
+			return nil, fmt.Errorf("item %d: ref %q is used more than once", i+1, spec.Ref)
+		}
+		refs[spec.Ref] = idAt(i)
+	}
+	resolve := func(s string) (string, error) {
+		var missing string
+		out := refRe.ReplaceAllStringFunc(s, func(m string) string {
+			name := refRe.FindStringSubmatch(m)[1]
+			id, ok := refs[name]
+			if !ok {
+				missing = name
+				return m
+			}
+			return id
+		})
+		if missing != "" {
+			return "", fmt.Errorf("unknown ref %q", missing)
+		}
+		return out, nil
+	}
+
+	var items []plannedItem
+	seen := make(map[string]bool)
+	for i, spec := range specs {
+		item, err := planItem(cfg, spec, idAt(i), idAt(len(specs)), resolve)
+		if err != nil {
+			label := spec.Title
+			if label == "" {
+				label = spec.Ref
+			}
+			return nil, fmt.Errorf("item %d (%s): %w", i+1, label, err)
+		}
+		if seen[item.Path] {
+			return nil, fmt.Errorf("item %d (%s): %s is created twice", i+1, spec.Title, item.Path)
+		}
+		seen[item.Path] = true
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func planItem(cfg *config.Config, spec itemSpec, id, nextFree string, resolve func(string) (string, error)) (plannedItem, error) {
+	var item plannedItem
+	if spec.Kind == "" {
+		return item, fmt.Errorf("kind is required")
+	}
+	tmplPath, err := templatePath(cfg, spec.Kind)
+	if err != nil {
+		return item, err
+	}
+	title, err := resolve(spec.Title)
+	if err != nil {
+		return item, err
+	}
+	if strings.TrimSpace(title) == "" {
+		return item, fmt.Errorf("title is required")
+	}
+	status := spec.Status
+	if status == "" {
+		status = cfg.DefaultStatus
+	}
+	folder, ok := cfg.StatusFolders[status]
+	if !ok {
+		return item, fmt.Errorf("unknown status '%s'", status)
+	}
+
+	inputs := map[string]string{
+		"id":      id,
+		"title":   title,
+		"status":  status,
+		"kind":    spec.Kind,
+		"created": inputDate(),
+	}
+	for k, v := range spec.Inputs {
+		value, err := resolve(specValue(v))
+		if err != nil {
+			return item, err
+		}
+		inputs[k] = value
+	}
+
+	tmpl, err := templates.LoadTemplate(tmplPath)
+	if err != nil {
+		return item, fmt.Errorf("failed to load template: %w", err)
+	}
+	if err := completeInputs(tmpl, spec.Kind, inputs, true); err != nil {
+		return item, err
+	}
+	opts := templates.DefaultRenderOptions()
+	opts.NextID = func() (string, error) { return nextFree, nil }
+	content, err := tmpl.Render(inputs, opts)
+	if err != nil {
+		return item, fmt.Errorf("failed to process template: %w", err)
+	}
+
+	if spec.Body != "" {
+		body, err := resolve(spec.Body)
+		if err != nil {
+			return item, err
+		}
+		content = replaceBody(content, body)
+	}
+
+	path := filepath.Join(".work", folder, workItemFilename(id, title, spec.Kind))
+	if _, err := os.Stat(path); err == nil {
+		return item, fmt.Errorf("%s already exists", path)
+	}
+	return plannedItem{ID: id, Path: path, Folder: folder, Content: content}, nil
+}
+
+// specValue turns a decoded input value into the string form --input takes
+func specValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			items = append(items, fmt.Sprint(item))
+		}
+		return strings.Join(items, ",")
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		// YAML decodes unquoted dates as timestamps
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return v.Format(templates.DefaultDateFormat)
+		}
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(v)
+}
+
+// replaceBody keeps the front matter of content and replaces the rest
+func replaceBody(content, body string) string {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > 0 && strings.TrimSpace(lines[0]) == "---" {
+		for i := 1; i < len(lines); i++ {
+			if strings.TrimSpace(lines[i]) == "---" {
+				return strings.Join(lines[:i+1], "") + "\n" + strings.TrimRight(body, "\n") + "\n"
+			}
+		}
+	}
+	return content
+}
+
+// writeItems writes every item, removing the ones already written if any
+// write fails
+func writeItems(items []plannedItem) error {
+	var written []string
+	for _, item := range items {
+		if err := os.WriteFile(item.Path, []byte(item.Content), 0644); err != nil {
+			for _, path := range written {
+				os.Remove(path)
+			}
+			return fmt.Errorf("failed to write work item file: %w", err)
+		}
+		written = append(written, item.Path)
+	}
+	return nil
+}
+
+// adoptWorkItem turns a hand-written markdown file into a work item: it gets
+// the next ID, the missing standard fields and a proper file name in its
+// status folder. kind is used when the front matter has none.
+func adoptWorkItem(cfg *config.Config, source, kind string) error {
+	data, err := os.ReadFile(source)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", source, err)
+	}
+	content := string(data)
+
+	frontMatter, body, ok := splitItemFrontMatter(content)
+	if !ok {
+		frontMatter, body = "", content
+	}
+	var fields map[string]interface{}
+	if err := yaml.Unmarshal([]byte(frontMatter), &fields); err != nil {
+		return fmt.Errorf("failed to parse front matter of %s: %w", source, err)
+	}
+	field := func(name string) string {
+		if v, ok := fields[name]; ok && v != nil {
+			return strings.TrimSpace(fmt.Sprint(v))
+		}
+		return ""
+	}
+
+	title := field("title")
+	if title == "" {
+		for _, line := range strings.Split(body, "\n") {
+			if strings.HasPrefix(line, "# ") {
+				title = strings.TrimSpace(strings.TrimPrefix(line, "# "))
+				break
+			}
+		}
+	}
+	if title == "" {
+		return fmt.Errorf("%s has no title: add a title field or a # heading", source)
+	}
+	if k := field("kind"); k != "" {
+		kind = k
+	}
+	if kind == "" {
+		return fmt.Errorf("%s has no kind: add a kind field or pass the template name", source)
+	}
+	if _, ok := cfg.Templates[kind]; !ok {
+		return fmt.Errorf("unknown kind '%s'", kind)
+	}
+	status := field("status")
+	if status == "" {
+		status = cfg.DefaultStatus
+	}
+	folder, ok := cfg.StatusFolders[status]
+	if !ok {
+		return fmt.Errorf("unknown status '%s'", status)
+	}
+
+	id, err := validation.GetNextID()
+	if err != nil {
+		return fmt.Errorf("failed to get next ID: %w", err)
+	}
+	created := field("created")
+	if created == "" {
+		created = inputDate()
+	}
+	frontMatter = setFrontMatterFields(frontMatter, [][2]string{
+		{"id", id},
+		{"title", title},
+		{"status", status},
+		{"kind", kind},
+		{"created", created},
+	})
+
+	path := filepath.Join(".work", folder, workItemFilename(id, title, kind))
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+	content = "---\n" + frontMatter + "---\n" + body
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write work item file: %w", err)
+	}
+
+	// A draft inside .work would otherwise be picked up as a second item
+	if rel, err := filepath.Rel(".work", source); err == nil && !strings.HasPrefix(rel, "..") {
+		if err := os.Remove(source); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", source, err)
+		}
+	}
+
+	fmt.Printf("Created work item %s in %s\n", id, folder)
+	return nil
+}
+
+// splitItemFrontMatter splits content into the front matter (without the
+// --- lines) and the body following it
+func splitItemFrontMatter(content string) (string, string, bool) {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "", content, false
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return strings.Join(lines[1:i], ""), strings.Join(lines[i+1:], ""), true
+		}
+	}
+	return "", content, false
+}
+
+// setFrontMatterFields replaces top-level keys in front matter. Keys that
+// are missing are added at the top, in the order given.
+func setFrontMatterFields(frontMatter string, fields [][2]string) string {
+	lines := strings.SplitAfter(frontMatter, "\n")
+	if frontMatter != "" && !strings.HasSuffix(frontMatter, "\n") {
+		lines[len(lines)-1] += "\n"
+	}
+	var head []string
+	for _, field := range fields {
+		line := fmt.Sprintf("%s: %s\n", field[0], yamlString(field[1]))
+		replaced := false
+		for i, l := range lines {
+			if strings.HasPrefix(l, field[0]+":") {
+				lines[i] = line
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			head = append(head, line)
+		}
+	}
+	return strings.Join(append(head, lines...), "")
+}
+
+// yamlString quotes a value when it would not read back as the same string
+func yamlString(value string) string {
+	var decoded struct {
+		V string `yaml:"v"`
+	}
+	if err := yaml.Unmarshal([]byte("v: "+value), &decoded); err == nil && decoded.V == value {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// inputDate is today's date in the format of the created field
+func inputDate() string {
+	return time.Now().Format(templates.DefaultDateFormat)
+}
@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestImportItems(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, initializeWorkspace(tmpDir))
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+	cfg := &config.DefaultConfig
+
+	specs, err := parseItemSpecs([]byte(`items:
+  - kind: task
+    title: Login form
+    status: todo
+    body: Part of {{ref:auth}}.
+  - ref: auth
+    kind: prd
+    title: User authentication
+    inputs:
+      tags: [security]
+`))
+	require.NoError(t, err)
+	require.NoError(t, createItems(cfg, specs, false))
+
+	content, err := os.ReadFile(".work/1_todo/001-login-form.task.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "id: 001")
+	assert.Contains(t, string(content), "---\n\nPart of 002.\n")
+
+	content, err = os.ReadFile(".work/0_backlog/002-user-authentication.prd.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "tags: [security]")
+
+	// One invalid item means nothing is written
+	specs, err = parseItemSpecs([]byte(`
+- kind: task
+  title: Valid
+- kind: task
+  title: Invalid
+  inputs:
+    estimate: lots
+`))
+	require.NoError(t, err)
+	err = createItems(cfg, specs, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "item 2 (Invalid)")
+	matches, _ := filepath.Glob(".work/0_backlog/*-valid.task.md")
+	assert.Empty(t, matches)
+
+	_, err = planItems(cfg, []itemSpec{{Kind: "task", Title: "See {{ref:nope}}"}})
+	assert.ErrorContains(t, err, `unknown ref "nope"`)
+}
+
+func TestAdoptWorkItem(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, initializeWorkspace(tmpDir))
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+	cfg := &config.DefaultConfig
+
+	draft := filepath.Join(".work", "0_backlog", "draft.md")
+	require.NoError(t, os.WriteFile(draft, []byte("---\npriority: high\n---\n# Hand written\n\nBody.\n"), 0644))
+
+	require.NoError(t, adoptWorkItem(cfg, draft, "task"))
+	content, err := os.ReadFile(".work/0_backlog/001-hand-written.task.md")
+	require.NoError(t, err)
+	assert.Equal(t, "---\nid: 001\ntitle: Hand written\nstatus: backlog\nkind: task\ncreated: "+inputDate()+"\npriority: high\n---\n# Hand written\n\nBody.\n", string(content))
+	assert.NoFileExists(t, draft)
+
+	require.NoError(t, os.WriteFile("loose.md", []byte("No title here\n"), 0644))
+	assert.ErrorContains(t, adoptWorkItem(cfg, "loose.md", "task"), "has no title")
+}
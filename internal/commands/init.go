@@ -1,7 +1,6 @@
 package commands
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -75,6 +74,7 @@ This file is for capturing quick ideas and thoughts that don't fit into formal w
 
 ## How to use
 - Add ideas with timestamps using ` + "`kira idea \"your idea here\"`" + `
+- List them with ` + "`kira idea list`" + ` and turn one into a work item with ` + "`kira idea promote <n> --kind <kind>`" + `
 - Or manually add entries below
 
 ## List
@@ -121,9 +121,7 @@ func ensureDirDecision(workPath string, force, fillMissing bool) error {
 	}
 
 	fmt.Println(".work already exists. Choose an option: [c]ancel, [o]verwrite, [f]ill-missing")
-	fmt.Print("Enter choice (c/o/f): ")
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
+	input, err := prompts.line("Enter choice (c/o/f): ")
 	if err != nil {
 		return err
 	}
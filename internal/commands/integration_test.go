@@ -262,10 +262,10 @@ Added user authentication system.
 		for _, templateFile := range templateFiles {
 			assert.FileExists(t, templateFile)
 
-			// Check that template contains input placeholders
+			// Check that template extends the shared base
 			content, err := os.ReadFile(templateFile)
 			require.NoError(t, err)
-			assert.Contains(t, string(content), "<!--input-")
+			assert.Contains(t, string(content), "extends: base")
 		}
 
 		// Test help-inputs command
@@ -378,7 +378,7 @@ created: 2024-01-01
 		output, err = abandonID.CombinedOutput()
 		require.NoError(t, err, "abandon by id failed: %s", string(output))
 		// Verify archived
-		archived1, _ := filepath.Glob(".work/z_archive/*/1_todo/001-todo-one.prd.md")
+		archived1, _ := filepath.Glob(".work/z_archive/*/001/001-todo-one.prd.md")
 		assert.NotEmpty(t, archived1)
 		assert.NoFileExists(t, ".work/1_todo/001-todo-one.prd.md")
 		// Verify abandonment note present
@@ -0,0 +1,502 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"kira/internal/archive"
+	"kira/internal/config"
+	"kira/internal/validation"
+)
+
+// itemSummary is a work item as listed by kira board and the JSON output
+type itemSummary struct {
+	ID       string   `json:"id"`
+	Title    string   `json:"title"`
+	Status   string   `json:"status"` // from the item's folder
+	Kind     string   `json:"kind"`
+	Assigned string   `json:"assigned,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+	Path     string   `json:"path"`
+}
+
+// itemFilter selects work items; empty fields match everything
+type itemFilter struct {
+	Assigned string
+	Tag      string
+	Kind     string
+}
+
+func (f itemFilter) matches(item itemSummary) bool {
+	if f.Kind != "" && f.Kind != item.Kind {
+		return false
+	}
+	if f.Assigned != "" && !strings.EqualFold(strings.TrimPrefix(f.Assigned, "@"), item.Assigned) {
+		return false
+	}
+	if f.Tag != "" && !containsString(item.Tags, strings.TrimPrefix(f.Tag, "#")) {
+		return false
+	}
+	return true
+}
+
+// listItems returns the work items in the status folders and their
+// subfolders, except the archive, that match filter, sorted by ID
+func listItems(cfg *config.Config, filter itemFilter) ([]itemSummary, error) {
+	var items []itemSummary
+	for _, status := range sortedStatuses(cfg) {
+		if status == "archived" {
+			continue
+		}
+		root := filepath.Join(".work", cfg.StatusFolders[status])
+		err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			// Skip the same files as validation: templates, IDEAS.md and bundles
+			if info.IsDir() || !strings.HasSuffix(path, ".md") ||
+				strings.Contains(path, "template") || strings.HasSuffix(path, "IDEAS.md") || archive.IsBundle(path) {
+				return nil
+			}
+			item, err := loadItemSummary(path)
+			if err != nil {
+				return nil // kira lint reports unreadable items
+			}
+			item.Status = status
+			if filter.matches(item) {
+				items = append(items, item)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s folder: %w", status, err)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items, nil
+}
+
+func loadItemSummary(path string) (itemSummary, error) {
+	wi, err := validation.ParseWorkItemFile(path)
+	if err != nil {
+		return itemSummary{}, err
+	}
+	item := itemSummary{ID: wi.ID, Title: wi.Title, Status: wi.Status, Kind: wi.Kind, Path: filepath.ToSlash(path)}
+	if assigned, ok := wi.Fields["assigned"].(string); ok {
+		item.Assigned = strings.TrimSpace(assigned)
+	}
+	switch tags := wi.Fields["tags"].(type) {
+	case []interface{}:
+		for _, tag := range tags {
+			item.Tags = append(item.Tags, fmt.Sprint(tag))
+		}
+	case string:
+		for _, tag := range strings.Split(tags, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				item.Tags = append(item.Tags, tag)
+			}
+		}
+	}
+	return item, nil
+}
+
+// itemDetail is a work item with its front matter and body
+type itemDetail struct {
+	itemSummary
+	Fields  map[string]interface{} `json:"fields"`
+	Body    string                 `json:"body"`
+	Content string                 `json:"content"`
+}
+
+// loadItemDetail reads the work item with the given ID
+func loadItemDetail(cfg *config.Config, id string) (itemDetail, error) {
+	path, err := findItemPath(cfg, id)
+	if err != nil {
+		return itemDetail{}, err
+	}
+	summary, err := loadItemSummary(path)
+	if err != nil {
+		return itemDetail{}, fmt.Errorf("failed to read work item: %w", err)
+	}
+	summary.Status = folderStatus(cfg, path, summary.Status)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return itemDetail{}, fmt.Errorf("failed to read work item: %w", err)
+	}
+	frontMatter, body, _ := splitItemFrontMatter(string(content))
+	detail := itemDetail{itemSummary: summary, Fields: make(map[string]interface{}), Body: body, Content: string(content)}
+	var doc yaml.Node
+	if yaml.Unmarshal([]byte(frontMatter), &doc) == nil && len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		m := doc.Content[0]
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			detail.Fields[m.Content[i].Value] = nodeValue(m.Content[i+1])
+		}
+	}
+	return detail, nil
+}
+
+// findItemPath finds a work item by ID, refusing IDs that do not match
+// validation.id_format
+func findItemPath(cfg *config.Config, id string) (string, error) {
+	if ok, _ := regexp.MatchString(cfg.Validation.IDFormat, id); !ok {
+		return "", fmt.Errorf("work item with ID %s %w", id, errWorkItemNotFound)
+	}
+	return findWorkItemFile(id)
+}
+
+// folderStatus is the status of the status folder holding path, directly
+// or in a subfolder, or fallback
+func folderStatus(cfg *config.Config, path, fallback string) string {
+	rel, err := filepath.Rel(".work", filepath.Clean(path))
+	if err != nil {
+		return fallback
+	}
+	folder := strings.Split(filepath.ToSlash(rel), "/")[0]
+	for status, f := range cfg.StatusFolders {
+		if f == folder {
+			return status
+		}
+	}
+	return fallback
+}
+
+// nodeValue decodes a front matter value for JSON. Numbers and booleans
+// become JSON ones only when that does not change how they read, so IDs
+// like 001 and dates stay as written.
+func nodeValue(n *yaml.Node) interface{} {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		list := []interface{}{}
+		for _, c := range n.Content {
+			list = append(list, nodeValue(c))
+		}
+		return list
+	case yaml.MappingNode:
+		m := make(map[string]interface{})
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			m[n.Content[i].Value] = nodeValue(n.Content[i+1])
+		}
+		return m
+	case yaml.AliasNode:
+		return nodeValue(n.Alias)
+	}
+	switch n.Tag {
+	case "!!null":
+		return nil
+	case "!!int", "!!float", "!!bool":
+		var v interface{}
+		if n.Decode(&v) == nil && fmt.Sprint(v) == n.Value {
+			return v
+		}
+	}
+	return n.Value
+}
+
+// updateItemFields sets front matter fields of a work item; a nil value
+// removes the field. Changing status moves the item to the status folder.
+func updateItemFields(cfg *config.Config, out io.Writer, id string, fields map[string]interface{}) error {
+	path, err := findItemPath(cfg, id)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read work item: %w", err)
+	}
+	frontMatter, body, ok := splitItemFrontMatter(string(content))
+	if !ok {
+		return fmt.Errorf("work item %s has no front matter", id)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		if key == "id" {
+			return fmt.Errorf("the id field cannot be changed")
+		}
+		if !fieldKeyRe.MatchString(key) {
+			return fmt.Errorf("invalid field name %q", key)
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	status := ""
+	for _, key := range keys {
+		value := fields[key]
+		if key == "status" {
+			s, ok := value.(string)
+			if _, known := cfg.StatusFolders[s]; !ok || !known {
+				return fmt.Errorf("invalid status: %v", value)
+			}
+			status = s
+		}
+		line := ""
+		if value != nil {
+			if line, err = yamlField(key, value); err != nil {
+				return err
+			}
+		}
+		frontMatter = replaceFrontMatterField(frontMatter, key, line)
+	}
+
+	if err := os.WriteFile(path, []byte("---\n"+frontMatter+"---\n"+body), 0644); err != nil {
+		return fmt.Errorf("failed to write work item: %w", err)
+	}
+	if status != "" && status != folderStatus(cfg, path, "") {
+		return moveWorkItem(cfg, out, id, status)
+	}
+	return nil
+}
+
+var fieldKeyRe = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// yamlField renders key: value as one line of front matter, with lists in
+// flow style
+func yamlField(key string, value interface{}) (string, error) {
+	var node yaml.Node
+	if err := node.Encode(value); err != nil {
+		return "", fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	if node.Kind == yaml.SequenceNode || node.Kind == yaml.MappingNode {
+		node.Style = yaml.FlowStyle
+	}
+	out, err := yaml.Marshal(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{{Kind: yaml.ScalarNode, Value: key}, &node}})
+	if err != nil {
+		return "", fmt.Errorf("invalid value for %s: %w", key, err)
+	}
+	return string(out), nil
+}
+
+// replaceFrontMatterField replaces a top-level field, with any indented or
+// list lines under it, by line; an empty line removes the field. Missing
+// fields are added at the end.
+func replaceFrontMatterField(frontMatter, key, line string) string {
+	lines := strings.SplitAfter(frontMatter, "\n")
+	var out []string
+	replaced := false
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], key+":") {
+			out = append(out, lines[i])
+			continue
+		}
+		for i+1 < len(lines) && (strings.HasPrefix(lines[i+1], " ") || strings.HasPrefix(lines[i+1], "\t") || strings.HasPrefix(lines[i+1], "- ")) {
+			i++
+		}
+		out = append(out, line)
+		replaced = true
+	}
+	result := strings.Join(out, "")
+	if !replaced && line != "" {
+		if result != "" && !strings.HasSuffix(result, "\n") {
+			result += "\n"
+		}
+		result += line
+	}
+	return result
+}
+
+// setItemContent replaces a work item's file. The ID must stay the same; a
+// changed status moves the item to the status folder.
+func setItemContent(cfg *config.Config, out io.Writer, id, content string) error {
+	path, err := findItemPath(cfg, id)
+	if err != nil {
+		return err
+	}
+	frontMatter, _, ok := splitItemFrontMatter(content)
+	if !ok {
+		return fmt.Errorf("content must start with front matter")
+	}
+	var fields struct {
+		ID     string `yaml:"id"`
+		Status string `yaml:"status"`
+	}
+	if err := yaml.Unmarshal([]byte(frontMatter), &fields); err != nil {
+		return fmt.Errorf("invalid front matter: %w", err)
+	}
+	if fields.ID != id {
+		return fmt.Errorf("the id field cannot be changed")
+	}
+	if _, known := cfg.StatusFolders[fields.Status]; fields.Status != "" && !known {
+		return fmt.Errorf("invalid status: %s", fields.Status)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write work item: %w", err)
+	}
+	if fields.Status != "" && fields.Status != folderStatus(cfg, path, "") {
+		return moveWorkItem(cfg, out, id, fields.Status)
+	}
+	return nil
+}
+
+// moveItem moves a work item to status, as kira move does, without
+// prompting for a missing status
+func moveItem(cfg *config.Config, out io.Writer, id, status string) error {
+	if _, ok := cfg.StatusFolders[status]; !ok {
+		return fmt.Errorf("invalid status: %q", status)
+	}
+	if _, err := findItemPath(cfg, id); err != nil {
+		return err
+	}
+	return moveWorkItem(cfg, out, id, status)
+}
+
+// claimNextItem assigns the first unassigned item in from that matches
+// filter to assignee and moves it to doing. It refuses while doing is at
+// its board.wip_limits limit.
+func claimNextItem(cfg *config.Config, out io.Writer, assignee, from string, filter itemFilter) (string, error) {
+	assignee = strings.TrimPrefix(strings.TrimSpace(assignee), "@")
+	if assignee == "" {
+		return "", fmt.Errorf("an assignee is required")
+	}
+	if from == "" {
+		from = "todo"
+	}
+	if _, ok := cfg.StatusFolders[from]; !ok {
+		return "", fmt.Errorf("invalid status: %q", from)
+	}
+	items, err := listItems(cfg, itemFilter{})
+	if err != nil {
+		return "", err
+	}
+	var doing []string
+	for _, item := range items {
+		if item.Status == "doing" {
+			doing = append(doing, item.ID)
+		}
+	}
+	if limit := wipLimit(cfg, "doing"); limit > 0 && len(doing) >= limit {
+		return "", fmt.Errorf("doing is at its WIP limit (%s); finish or move that work first", strings.Join(doing, ", "))
+	}
+	for _, item := range items {
+		if item.Status != from || item.Assigned != "" || !filter.matches(item) {
+			continue
+		}
+		if err := updateItemFields(cfg, out, item.ID, map[string]interface{}{"assigned": assignee, "status": "doing"}); err != nil {
+			return "", err
+		}
+		return item.ID, nil
+	}
+	return "", fmt.Errorf("no unassigned items in %s to claim", from)
+}
+
+// commentsHeading is the body section comments are added to
+const commentsHeading = "## Comments"
+
+// addItemComment appends a timestamped comment to the Comments section of a
+// work item, creating the section at the end when there is none
+func addItemComment(cfg *config.Config, id, author, text string) error {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return fmt.Errorf("comment text is required")
+	}
+	path, err := findItemPath(cfg, id)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read work item: %w", err)
+	}
+
+	comment := fmt.Sprintf("- [%s] %s", time.Now().UTC().Format(ideaTimeFormat), strings.ReplaceAll(text, "\n", "\n  "))
+	if author = strings.TrimSpace(author); author != "" {
+		comment += fmt.Sprintf(" (by %s)", author)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(content), "\n"), "\n")
+	at := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == commentsHeading {
+			at = len(lines)
+			for j := i + 1; j < len(lines); j++ {
+				if strings.HasPrefix(lines[j], "# ") || strings.HasPrefix(lines[j], "## ") {
+					at = j
+					break
+				}
+			}
+			break
+		}
+	}
+	if at < 0 {
+		lines = append(lines, "", commentsHeading, "", comment)
+	} else {
+		end := at
+		for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+		rest := append([]string{comment}, lines[at:]...)
+		if at < len(lines) {
+			rest = append([]string{comment, ""}, lines[at:]...)
+		}
+		lines = append(lines[:end], rest...)
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write work item: %w", err)
+	}
+	return nil
+}
+
+// searchHit is a work item whose content matches a search
+type searchHit struct {
+	itemSummary
+	Matches []string `json:"matches"` // matching lines, at most three
+}
+
+// searchItems finds the non-archived work items containing query, ignoring
+// case
+func searchItems(cfg *config.Config, query string) ([]searchHit, error) {
+	items, err := listItems(cfg, itemFilter{})
+	if err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(strings.TrimSpace(query))
+	hits := []searchHit{}
+	if query == "" {
+		return hits, nil
+	}
+	for _, item := range items {
+		content, err := os.ReadFile(item.Path)
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(strings.ToLower(string(content)), query) {
+			continue
+		}
+		hit := searchHit{itemSummary: item, Matches: []string{}}
+		for _, line := range strings.Split(string(content), "\n") {
+			if len(hit.Matches) < 3 && strings.Contains(strings.ToLower(line), query) {
+				hit.Matches = append(hit.Matches, strings.TrimSpace(line))
+			}
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// lintIssue is a validation error in JSON form
+type lintIssue struct {
+	File    string `json:"file"`
+	Message string `json:"message"`
+}
+
+// lintIssues validates every work item, as kira lint does
+func lintIssues(cfg *config.Config) ([]lintIssue, error) {
+	result, err := validation.ValidateWorkItems(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate work items: %w", err)
+	}
+	issues := []lintIssue{}
+	for _, e := range result.Errors {
+		issues = append(issues, lintIssue{File: e.File, Message: e.Message})
+	}
+	return issues, nil
+}
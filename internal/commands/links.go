@@ -0,0 +1,331 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"kira/internal/config"
+	"kira/internal/release"
+	"kira/internal/templates"
+	"kira/internal/validation"
+)
+
+var showCmd = &cobra.Command{
+	Use:   "show <work-item-id>",
+	Short: "Show a work item",
+	Long: `Prints a work item's path and content. With --commits, also lists the
+code commits linked to it, with the files each touched. A commit is linked
+when its message references the item (Refs: #012, Fixes #012) or it was made
+on a branch named after the item (012-login-fix).`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		withCommits, _ := cmd.Flags().GetBool("commits")
+		return showWorkItem(cfg, args[0], withCommits)
+	},
+}
+
+var branchCmd = &cobra.Command{
+	Use:   "branch <work-item-id>",
+	Short: "Create a git branch for a work item and start it",
+	Long: `Creates and switches to a branch named from the work item's ID and title,
+such as 012-login-fix, and moves the item to doing. Commits on the branch
+are linked to the item (see 'kira show --commits').`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		return branchWorkItem(cfg, args[0])
+	},
+}
+
+func init() {
+	showCmd.Flags().Bool("commits", false, "List the commits linked to the work item")
+}
+
+// linkedCommit is a commit tied to a work item by a reference in its message
+// or by the name of the branch it was made on
+type linkedCommit struct {
+	Hash    string
+	Author  string
+	Date    string
+	Subject string
+	Via     string // "message", or "branch <name>"
+	Files   []string
+}
+
+// commitLinks maps work item IDs to the hashes of their linked commits and
+// how each was linked
+type commitLinks map[string]map[string]string
+
+var mergeBranchRe = regexp.MustCompile(`^Merge (?:remote-tracking )?branch '([^']+)'|^Merge pull request #\d+ from (\S+)`)
+
+// branchItemID is the work item ID a branch is named after, e.g. 012 for
+// 012-login-fix or origin/012-login-fix, or "" if there is none
+func branchItemID(cfg *config.Config, branch string) string {
+	name := path.Base(branch)
+	i := strings.Index(name, "-")
+	if i <= 0 {
+		return ""
+	}
+	if ok, _ := regexp.MatchString(cfg.Validation.IDFormat, name[:i]); !ok {
+		return ""
+	}
+	return name[:i]
+}
+
+// scanCommitLinks finds the commits linked to every work item across all
+// branches. kira save commits are bookkeeping and never linked.
+func scanCommitLinks(cfg *config.Config) (commitLinks, error) {
+	links := make(commitLinks)
+	if !isGitRepo() {
+		return links, nil
+	}
+	link := func(id, hash, via string) {
+		if links[id] == nil {
+			links[id] = make(map[string]string)
+		}
+		if _, seen := links[id][hash]; !seen {
+			links[id][hash] = via
+		}
+	}
+
+	// References in commit messages
+	out, err := runGit("log", "--all", "--format=%x1e%H%x1f%P%x1f%B")
+	if err != nil {
+		return nil, err
+	}
+	bookkeeping := make(map[string]bool)
+	type merge struct{ hash, first, second, branch string }
+	var merges []merge
+	for _, record := range strings.Split(out, "\x1e") {
+		fields := strings.SplitN(record, "\x1f", 3)
+		if len(fields) < 3 {
+			continue
+		}
+		hash, parents, message := fields[0], strings.Fields(fields[1]), fields[2]
+		if strings.Contains(message, "\n"+itemsTrailer+":") {
+			bookkeeping[hash] = true
+			continue
+		}
+		for _, ref := range itemRefs(cfg, message) {
+			link(ref.ID, hash, "message")
+		}
+		if m := mergeBranchRe.FindStringSubmatch(message); m != nil && len(parents) > 1 {
+			merges = append(merges, merge{hash, parents[0], parents[1], m[1] + m[2]})
+		}
+	}
+
+	linkRange := func(id, via string, args ...string) error {
+		out, err := runGit(append([]string{"rev-list"}, args...)...)
+		if err != nil {
+			return err
+		}
+		for _, hash := range strings.Fields(out) {
+			if !bookkeeping[hash] {
+				link(id, hash, via)
+			}
+		}
+		return nil
+	}
+
+	// Commits brought in by merging an item's branch
+	for _, m := range merges {
+		if id := branchItemID(cfg, m.branch); id != "" {
+			if err := linkRange(id, "branch "+path.Base(m.branch), m.second, "--not", m.first); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Commits on item branches that no other branch has
+	out, err = runGit("for-each-ref", "--format=%(refname)", "refs/heads", "refs/remotes")
+	if err != nil {
+		return nil, err
+	}
+	branches := make(map[string]string)
+	for _, ref := range strings.Fields(out) {
+		if strings.HasSuffix(ref, "/HEAD") {
+			continue
+		}
+		name := strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/remotes/")
+		branches[ref] = branchItemID(cfg, name)
+	}
+	for ref, id := range branches {
+		if id == "" {
+			continue
+		}
+		var others []string
+		for other, otherID := range branches {
+			if otherID != id {
+				others = append(others, other)
+			}
+		}
+		if len(others) == 0 {
+			continue // without another branch the whole history would count
+		}
+		name := path.Base(ref)
+		if err := linkRange(id, "branch "+name, append([]string{ref, "--not"}, others...)...); err != nil {
+			return nil, err
+		}
+	}
+	return links, nil
+}
+
+// commits returns the commits linked to a work item, newest first, with the
+// files each touched
+func (l commitLinks) commits(id string) ([]linkedCommit, error) {
+	if len(l[id]) == 0 {
+		return nil, nil
+	}
+	args := []string{"log", "--no-walk=sorted", "--name-only", "--format=%x1e%H%x1f%an%x1f%aI%x1f%s"}
+	for hash := range l[id] {
+		args = append(args, hash)
+	}
+	out, err := runGit(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []linkedCommit
+	for _, record := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		meta := strings.Split(lines[0], "\x1f")
+		if len(meta) < 4 {
+			continue
+		}
+		c := linkedCommit{Hash: meta[0], Author: meta[1], Date: meta[2], Subject: meta[3], Via: l[id][meta[0]]}
+		for _, file := range lines[1:] {
+			if file = strings.TrimSpace(file); file != "" {
+				c.Files = append(c.Files, file)
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// releaseCommits returns an item's linked commits for the release notes
+func (l commitLinks) releaseCommits(id string) ([]release.Commit, error) {
+	linked, err := l.commits(id)
+	if err != nil {
+		return nil, err
+	}
+	var commits []release.Commit
+	for _, c := range linked {
+		commits = append(commits, release.Commit{Hash: c.Hash, Author: c.Author, Date: c.Date, Subject: c.Subject})
+	}
+	return commits, nil
+}
+
+func showWorkItem(cfg *config.Config, id string, withCommits bool) error {
+	workItemPath, err := findWorkItemFile(id)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(workItemPath)
+	if err != nil {
+		return fmt.Errorf("failed to read work item: %w", err)
+	}
+	fmt.Printf("%s\n\n%s", workItemPath, content)
+	if !withCommits {
+		return nil
+	}
+
+	links, err := scanCommitLinks(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to find linked commits: %w", err)
+	}
+	commits, err := links.commits(id)
+	if err != nil {
+		return fmt.Errorf("failed to find linked commits: %w", err)
+	}
+	fmt.Println()
+	if len(commits) == 0 {
+		fmt.Printf("No commits linked to %s. Reference it with 'Refs: #%s' or commit on a branch from 'kira branch %s'.\n", id, id, id)
+		return nil
+	}
+	fmt.Printf("Linked commits (%d):\n", len(commits))
+	for _, c := range commits {
+		date := c.Date
+		if len(date) >= 10 {
+			date = date[:10]
+		}
+		line := fmt.Sprintf("%s  %s  %s  %s", shortHash(c.Hash), date, c.Author, c.Subject)
+		if c.Via != "message" {
+			line += fmt.Sprintf("  [%s]", c.Via)
+		}
+		fmt.Println(line)
+		for _, file := range c.Files {
+			fmt.Printf("    %s\n", file)
+		}
+	}
+	return nil
+}
+
+// reachedStatus reports whether the item at workItemPath is already in status or a
+// later one, going by the order of the status folders, or archived
+func reachedStatus(cfg *config.Config, workItemPath, status string) bool {
+	archiveRoot := filepath.Join(".work", cfg.StatusFolders["archived"])
+	if strings.HasPrefix(workItemPath, archiveRoot+string(filepath.Separator)) {
+		return true
+	}
+	return !folderBefore(filepath.Base(filepath.Dir(workItemPath)), cfg.StatusFolders[status])
+}
+
+// branchWorkItem creates and switches to the branch for a work item and
+// moves the item to doing
+func branchWorkItem(cfg *config.Config, id string) error {
+	if !isGitRepo() {
+		return fmt.Errorf("not a git repository: run 'git init' first")
+	}
+	workItemPath, err := findWorkItemFile(id)
+	if err != nil {
+		return err
+	}
+	item, err := validation.ParseWorkItemFile(workItemPath)
+	if err != nil {
+		return fmt.Errorf("failed to read work item: %w", err)
+	}
+
+	archiveRoot := filepath.Join(".work", cfg.StatusFolders["archived"])
+	switch {
+	case strings.HasPrefix(workItemPath, archiveRoot+string(filepath.Separator)):
+		return fmt.Errorf("work item %s is archived", id)
+	case item.Status == "released" || item.Status == "abandoned":
+		return fmt.Errorf("work item %s is %s", id, item.Status)
+	}
+
+	branch := item.ID
+	if slug := templates.Slug(item.Title); slug != "" {
+		branch += "-" + slug
+	}
+	if _, err := runGit("rev-parse", "--verify", "--quiet", "refs/heads/"+branch); err == nil {
+		return fmt.Errorf("branch %s already exists; switch to it with 'git switch %s'", branch, branch)
+	}
+	if _, err := runGit("switch", "-c", branch); err != nil {
+		return fmt.Errorf("failed to create branch: %w", err)
+	}
+	fmt.Printf("Switched to a new branch '%s'\n", branch)
+
+	if reachedStatus(cfg, workItemPath, "doing") {
+		return nil
+	}
+	return moveWorkItem(cfg, os.Stdout, id, "doing")
+}
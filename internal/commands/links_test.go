@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+	"kira/internal/release"
+	"kira/internal/templates"
+)
+
+func TestBranchAndLinkedCommits(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	git := func(args ...string) {
+		_, err := runGit(args...)
+		require.NoError(t, err)
+	}
+	commitFile := func(name, message string) {
+		require.NoError(t, os.WriteFile(name, []byte(name+"\n"), 0644))
+		git("add", name)
+		git("commit", "-q", "-m", message)
+	}
+
+	item := "---\nid: 001\ntitle: Login Fix\nstatus: todo\nkind: task\ncreated: 2024-01-01\n---\n# Login Fix\n"
+	require.NoError(t, os.WriteFile(".work/1_todo/001-login-fix.task.md", []byte(item), 0644))
+	require.NoError(t, saveWorkItems(cfg, os.Stdout, "", saveOptions{}))
+	base, err := runGit("rev-parse", "--abbrev-ref", "HEAD")
+	require.NoError(t, err)
+
+	require.NoError(t, branchWorkItem(cfg, "001"))
+	branch, err := runGit("rev-parse", "--abbrev-ref", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "001-login-fix\n", branch)
+	assert.FileExists(t, ".work/2_doing/001-login-fix.task.md")
+	assert.Error(t, branchWorkItem(cfg, "001"), "the branch exists")
+
+	require.NoError(t, saveWorkItems(cfg, os.Stdout, "", saveOptions{}))
+	commitFile("login.go", "Fix login")
+	git("switch", "-q", strings.TrimSpace(base))
+	commitFile("docs.md", "Document login\n\nRefs: #001")
+	commitFile("other.go", "Unrelated")
+
+	links, err := scanCommitLinks(cfg)
+	require.NoError(t, err)
+	commits, err := links.commits("001")
+	require.NoError(t, err)
+	require.Len(t, commits, 2, "kira save commits are not linked")
+	bySubject := map[string]linkedCommit{}
+	for _, c := range commits {
+		bySubject[c.Subject] = c
+	}
+	assert.Equal(t, "branch 001-login-fix", bySubject["Fix login"].Via)
+	assert.Equal(t, []string{"login.go"}, bySubject["Fix login"].Files)
+	assert.Equal(t, "message", bySubject["Document login"].Via)
+
+	// Merged and deleted, the branch's commits stay linked through the merge
+	git("merge", "-q", "--no-ff", "-m", "Merge branch '001-login-fix'", "001-login-fix")
+	git("branch", "-q", "-D", "001-login-fix")
+	links, err = scanCommitLinks(cfg)
+	require.NoError(t, err)
+	released, err := links.releaseCommits("001")
+	require.NoError(t, err)
+	var subjects []string
+	for _, c := range released {
+		subjects = append(subjects, c.Subject)
+	}
+	assert.ElementsMatch(t, []string{"Fix login", "Document login"}, subjects)
+}
+
+func TestReleaseUsesCommits(t *testing.T) {
+	cfg := &config.Config{}
+	assert.False(t, releaseUsesCommits(cfg, "{{ range .Groups }}{{ .Title }}{{ end }}"))
+	assert.True(t, releaseUsesCommits(cfg, "{{ range .Commits }}{{ .Subject }}{{ end }}"))
+	cfg.Release.Outputs = []config.ReleaseOutput{{Type: release.OutputJSON, Path: "releases.json"}}
+	assert.True(t, releaseUsesCommits(cfg, ""), "the json output lists commits")
+}
+
+func TestBranchWorkItemRefusals(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	assert.Equal(t, "fix-login-now", templates.Slug("Fix login? now"))
+	assert.Equal(t, "a-b-c", templates.Slug("~a: b^c.."))
+
+	require.NoError(t, os.MkdirAll(".work/z_archive/2024-01-01", 0755))
+	archived := "---\nid: 002\ntitle: Old\nstatus: done\nkind: task\ncreated: 2024-01-01\n---\n"
+	require.NoError(t, os.WriteFile(".work/z_archive/2024-01-01/002-old.task.md", []byte(archived), 0644))
+	assert.ErrorContains(t, branchWorkItem(cfg, "002"), "archived")
+
+	released := "---\nid: 003\ntitle: Shipped\nstatus: released\nkind: task\ncreated: 2024-01-01\n---\n"
+	require.NoError(t, os.WriteFile(".work/4_done/003-shipped.task.md", []byte(released), 0644))
+	assert.ErrorContains(t, branchWorkItem(cfg, "003"), "released")
+
+	item := "---\nid: 004\ntitle: Fix login? now\nstatus: todo\nkind: task\ncreated: 2024-01-01\n---\n"
+	require.NoError(t, os.WriteFile(".work/1_todo/004-fix-login.task.md", []byte(item), 0644))
+	require.NoError(t, branchWorkItem(cfg, "004"))
+	branch, err := runGit("rev-parse", "--abbrev-ref", "HEAD")
+	require.NoError(t, err)
+	assert.Equal(t, "004-fix-login-now\n", branch)
+}
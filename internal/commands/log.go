@@ -0,0 +1,306 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"kira/internal/config"
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log <work-item-id>",
+	Short: "Show the history of a work item from git",
+	Long: `Shows every commit that touched a work item, following its file through
+moves between status folders and into the archive. Each commit lists the
+status transitions, front matter field changes and body sections edited.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		entries, err := itemHistory(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(entries)
+		}
+		printItemHistory(entries)
+		return nil
+	},
+}
+
+func init() {
+	logCmd.Flags().Bool("json", false, "Print the history as JSON")
+}
+
+// historyEntry is one commit in the history of a work item
+type historyEntry struct {
+	Commit  string          `json:"commit"`
+	Author  string          `json:"author"`
+	Email   string          `json:"email"`
+	Date    string          `json:"date"`
+	Subject string          `json:"subject"`
+	Path    string          `json:"path,omitempty"` // the item's file after the commit; empty once deleted
+	Changes []historyChange `json:"changes"`
+}
+
+// historyChange is a single change to a work item within a commit. Type is
+// created, moved, archived, deleted, status, field or section.
+type historyChange struct {
+	Type  string `json:"type"`
+	Field string `json:"field,omitempty"` // field name, or section heading
+	From  string `json:"from,omitempty"`
+	To    string `json:"to,omitempty"`
+}
+
+// itemSnapshot is a work item's file as of one commit
+type itemSnapshot struct {
+	path        string
+	frontMatter map[string]string
+	fieldOrder  []string
+	sections    map[string]string
+	headings    []string
+}
+
+// historyIgnoredFields change with every save or never change, so they are
+// left out of the timeline
+var historyIgnoredFields = map[string]bool{"id": true, "updated": true, "status": true}
+
+// itemHistory walks the commits touching any file named <id>-*.md under
+// .work, oldest first. The ID prefix survives moves, archival and title
+// changes, so no rename detection is needed.
+func itemHistory(cfg *config.Config, id string) ([]historyEntry, error) {
+	if !isGitRepo() {
+		return nil, fmt.Errorf("not a git repository: kira log reads work item history from git")
+	}
+	const sep = "\x1f"
+	out, err := runGit("log", "--reverse", "--name-only", "--relative",
+		"--format=%x1e%H"+sep+"%an"+sep+"%ae"+sep+"%aI"+sep+"%s",
+		"--", fmt.Sprintf(":(glob).work/**/%s-*.md", id))
+	if err != nil {
+		return nil, err
+	}
+
+	archiveRoot := ".work/" + cfg.StatusFolders["archived"] + "/"
+	var entries []historyEntry
+	var prev *itemSnapshot
+	for _, record := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		meta := strings.Split(lines[0], sep)
+		if len(meta) < 5 {
+			continue
+		}
+		entry := historyEntry{Commit: meta[0], Author: meta[1], Email: meta[2], Date: meta[3], Subject: meta[4]}
+
+		// The item's file in this commit, if it still exists
+		var cur *itemSnapshot
+		for _, path := range lines[1:] {
+			path = strings.TrimSpace(path)
+			if path == "" {
+				continue
+			}
+			content, err := runGit("show", entry.Commit+":./"+path)
+			if err != nil {
+				continue
+			}
+			snap := parseItemSnapshot(path, content)
+			if snap.frontMatter["id"] != id {
+				continue
+			}
+			cur = snap
+			break
+		}
+
+		entry.Changes = diffSnapshots(prev, cur, archiveRoot)
+		if cur != nil {
+			entry.Path = cur.path
+		}
+		if len(entry.Changes) > 0 {
+			entries = append(entries, entry)
+		}
+		if cur != nil || prev != nil {
+			prev = cur
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no committed history for work item %s", id)
+	}
+	return entries, nil
+}
+
+func parseItemSnapshot(path, content string) *itemSnapshot {
+	snap := &itemSnapshot{path: path, frontMatter: make(map[string]string), sections: make(map[string]string)}
+	frontMatter, body, ok := splitItemFrontMatter(content)
+	if !ok {
+		body = content
+	}
+
+	var doc yaml.Node
+	if yaml.Unmarshal([]byte(frontMatter), &doc) == nil && len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+		m := doc.Content[0]
+		for i := 0; i+1 < len(m.Content); i += 2 {
+			key := m.Content[i].Value
+			value := m.Content[i+1].Value
+			if m.Content[i+1].Kind != yaml.ScalarNode {
+				out, _ := yaml.Marshal(m.Content[i+1])
+				value = strings.TrimSpace(string(out))
+			}
+			snap.frontMatter[key] = value
+			snap.fieldOrder = append(snap.fieldOrder, key)
+		}
+	}
+
+	heading := ""
+	var buf []string
+	flush := func() {
+		if _, seen := snap.sections[heading]; !seen {
+			snap.headings = append(snap.headings, heading)
+		}
+		snap.sections[heading] += strings.TrimSpace(strings.Join(buf, "\n"))
+		buf = nil
+	}
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(line, "# ") {
+			continue // the title, already tracked as a field
+		}
+		if strings.HasPrefix(line, "#") {
+			flush()
+			heading = strings.TrimSpace(strings.TrimLeft(line, "#"))
+			continue
+		}
+		buf = append(buf, line)
+	}
+	flush()
+	return snap
+}
+
+// diffSnapshots lists the changes from prev to cur; either may be nil when
+// the item does not exist on that side
+func diffSnapshots(prev, cur *itemSnapshot, archiveRoot string) []historyChange {
+	switch {
+	case prev == nil && cur == nil:
+		return nil
+	case prev == nil:
+		return []historyChange{
+			{Type: "created", To: cur.path},
+			{Type: "status", To: cur.frontMatter["status"]},
+		}
+	case cur == nil:
+		return []historyChange{{Type: "deleted", From: prev.path}}
+	}
+
+	var changes []historyChange
+	if prev.path != cur.path {
+		kind := "moved"
+		if strings.HasPrefix(cur.path, archiveRoot) && !strings.HasPrefix(prev.path, archiveRoot) {
+			kind = "archived"
+		}
+		changes = append(changes, historyChange{Type: kind, From: prev.path, To: cur.path})
+	}
+	if prev.frontMatter["status"] != cur.frontMatter["status"] {
+		changes = append(changes, historyChange{Type: "status", From: prev.frontMatter["status"], To: cur.frontMatter["status"]})
+	}
+
+	fields := append([]string{}, cur.fieldOrder...)
+	for _, f := range prev.fieldOrder {
+		if _, ok := cur.frontMatter[f]; !ok {
+			fields = append(fields, f)
+		}
+	}
+	for _, f := range fields {
+		if historyIgnoredFields[f] || prev.frontMatter[f] == cur.frontMatter[f] {
+			continue
+		}
+		changes = append(changes, historyChange{Type: "field", Field: f, From: prev.frontMatter[f], To: cur.frontMatter[f]})
+	}
+
+	headings := append([]string{}, cur.headings...)
+	for _, h := range prev.headings {
+		if _, ok := cur.sections[h]; !ok {
+			headings = append(headings, h)
+		}
+	}
+	for _, h := range headings {
+		before, hadBefore := prev.sections[h]
+		after, hasAfter := cur.sections[h]
+		if before == after {
+			continue
+		}
+		change := historyChange{Type: "section", Field: h}
+		switch {
+		case !hadBefore:
+			change.To = "added"
+		case !hasAfter:
+			change.To = "removed"
+		default:
+			change.To = "edited"
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func printItemHistory(entries []historyEntry) {
+	for i, entry := range entries {
+		if i > 0 {
+			fmt.Println()
+		}
+		date := entry.Date
+		if len(date) >= 10 {
+			date = date[:10]
+		}
+		fmt.Printf("%s  %s  %s <%s>  %s\n", shortHash(entry.Commit), date, entry.Author, entry.Email, entry.Subject)
+		for _, c := range entry.Changes {
+			fmt.Printf("    %s\n", describeHistoryChange(c))
+		}
+	}
+}
+
+func describeHistoryChange(c historyChange) string {
+	orNone := func(s string) string {
+		if s == "" {
+			return "(none)"
+		}
+		return s
+	}
+	switch c.Type {
+	case "created":
+		return fmt.Sprintf("created %s", c.To)
+	case "deleted":
+		return fmt.Sprintf("deleted %s", c.From)
+	case "moved", "archived":
+		return fmt.Sprintf("%s %s → %s", c.Type, c.From, c.To)
+	case "status":
+		return fmt.Sprintf("status: %s → %s", orNone(c.From), orNone(c.To))
+	case "field":
+		return fmt.Sprintf("%s: %s → %s", c.Field, orNone(c.From), orNone(c.To))
+	case "section":
+		heading := c.Field
+		if heading == "" {
+			heading = "(preamble)"
+		}
+		return fmt.Sprintf("section %q %s", heading, c.To)
+	}
+	return c.Type
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
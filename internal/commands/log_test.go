@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestItemHistory(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	commit := func(message string) {
+		_, err := runGit("add", "-A")
+		require.NoError(t, err)
+		_, err = runGit("commit", "-q", "-m", message)
+		require.NoError(t, err)
+	}
+
+	item := "---\nid: 012\ntitle: Login fix\nstatus: todo\nkind: task\nassigned: \ncreated: 2024-01-01\n---\n# Login fix\n\n## Requirements\nTBD\n\n## Notes\n"
+	require.NoError(t, os.WriteFile(".work/1_todo/012-login-fix.task.md", []byte(item), 0644))
+	commit("create 012")
+
+	// An unrelated item whose slug starts like the ID is not followed
+	other := "---\nid: 099\ntitle: 012 other\nstatus: todo\nkind: task\ncreated: 2024-01-01\n---\n"
+	require.NoError(t, os.WriteFile(".work/1_todo/099-012-other.task.md", []byte(other), 0644))
+	commit("create 099")
+
+	item = "---\nid: 012\ntitle: Login fix\nstatus: doing\nkind: task\nassigned: bob@example.com\ncreated: 2024-01-01\nupdated: 2024-01-02T10:00:00Z\n---\n# Login fix\n\n## Requirements\nSupport SSO\n\n## Notes\n"
+	require.NoError(t, os.MkdirAll(".work/2_doing", 0755))
+	require.NoError(t, os.Remove(".work/1_todo/012-login-fix.task.md"))
+	require.NoError(t, os.WriteFile(".work/2_doing/012-login-fix.task.md", []byte(item), 0644))
+	commit("start 012")
+
+	entries, err := itemHistory(cfg, "012")
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, "create 012", entries[0].Subject)
+	assert.Equal(t, "Test User", entries[0].Author)
+	assert.Equal(t, []historyChange{
+		{Type: "created", To: ".work/1_todo/012-login-fix.task.md"},
+		{Type: "status", To: "todo"},
+	}, entries[0].Changes)
+
+	assert.Equal(t, []historyChange{
+		{Type: "moved", From: ".work/1_todo/012-login-fix.task.md", To: ".work/2_doing/012-login-fix.task.md"},
+		{Type: "status", From: "todo", To: "doing"},
+		{Type: "field", Field: "assigned", To: "bob@example.com"},
+		{Type: "section", Field: "Requirements", To: "edited"},
+	}, entries[1].Changes)
+	assert.Equal(t, "assigned: (none) → bob@example.com", describeHistoryChange(entries[1].Changes[2]))
+
+	_, err = itemHistory(cfg, "404")
+	assert.Error(t, err)
+}
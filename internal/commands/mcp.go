@@ -0,0 +1,471 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"kira/internal/config"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol server over stdio",
+	Long: `Runs a Model Context Protocol (MCP) server on stdin and stdout, so agents
+can list, read, create, update, move, comment on, claim, lint and save work
+items without parsing command output. Every work item and kira.yml are also
+available as resources.
+
+Register it with an MCP client as the command "kira mcp", started in the
+workspace directory.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		return serveMCP(cfg, os.Stdin, os.Stdout)
+	},
+}
+
+// mcpProtocolVersions are the MCP revisions kira speaks, newest first
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+const mcpInstructions = `kira keeps work items as markdown files with YAML front matter in .work/<status folder>/. ` +
+	`Use list_items to see the work, claim_next to pick something up, update_fields and add_comment while working, ` +
+	`move_item when it is ready for the next status, lint to check the workspace and save to commit it.`
+
+// JSON-RPC error codes
+const (
+	rpcParseError       = -32700
+	rpcMethodNotFound   = -32601
+	rpcInvalidParams    = -32602
+	mcpResourceNotFound = -32002
+)
+
+type rpcRequest struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// serveMCP answers newline-delimited JSON-RPC messages from in on out until
+// in is closed
+func serveMCP(cfg *config.Config, in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(out)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var req rpcRequest
+		if err := json.Unmarshal(line, &req); err != nil {
+			enc.Encode(map[string]interface{}{"jsonrpc": "2.0", "id": nil, "error": rpcError{rpcParseError, "parse error: " + err.Error()}})
+			continue
+		}
+		result, err := handleMCP(cfg, req.Method, req.Params)
+		if len(req.ID) == 0 {
+			continue // notifications get no response
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": req.ID}
+		if err != nil {
+			resp["error"] = err
+		} else {
+			resp["result"] = result
+		}
+		if err := enc.Encode(resp); err != nil {
+			return fmt.Errorf("failed to write response: %w", err)
+		}
+	}
+	return scanner.Err()
+}
+
+func handleMCP(cfg *config.Config, method string, params json.RawMessage) (interface{}, *rpcError) {
+	switch method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		json.Unmarshal(params, &p)
+		version := mcpProtocolVersions[0]
+		if containsString(mcpProtocolVersions, p.ProtocolVersion) {
+			version = p.ProtocolVersion
+		}
+		return map[string]interface{}{
+			"protocolVersion": version,
+			"capabilities":    map[string]interface{}{"tools": map[string]interface{}{}, "resources": map[string]interface{}{}},
+			"serverInfo":      map[string]string{"name": "kira", "version": Version},
+			"instructions":    mcpInstructions,
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": mcpTools(cfg)}, nil
+	case "tools/call":
+		var p struct {
+			Name      string          `json:"name"`
+			Arguments json.RawMessage `json:"arguments"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		for _, tool := range mcpTools(cfg) {
+			if tool.Name == p.Name {
+				return callTool(tool, p.Arguments), nil
+			}
+		}
+		return nil, &rpcError{rpcInvalidParams, fmt.Sprintf("unknown tool %q", p.Name)}
+	case "resources/list":
+		resources, err := mcpResources(cfg)
+		if err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		return map[string]interface{}{"resources": resources}, nil
+	case "resources/templates/list":
+		return map[string]interface{}{"resourceTemplates": []map[string]string{{
+			"uriTemplate": mcpItemURI + "{id}",
+			"name":        "Work item",
+			"description": "A work item's markdown file, by ID",
+			"mimeType":    "text/markdown",
+		}}}, nil
+	case "resources/read":
+		var p struct {
+			URI string `json:"uri"`
+		}
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &rpcError{rpcInvalidParams, err.Error()}
+		}
+		return readMCPResource(cfg, p.URI)
+	}
+	if strings.HasPrefix(method, "notifications/") {
+		return nil, nil
+	}
+	return nil, &rpcError{rpcMethodNotFound, fmt.Sprintf("method %q not found", method)}
+}
+
+// mcpTool is a tool with its JSON schema. call decodes the arguments into
+// its own struct and returns a JSON object.
+type mcpTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+	call        func(args json.RawMessage) (interface{}, error)
+}
+
+// callTool runs a tool. Failures are reported to the agent as a tool result
+// with isError set, so it can correct itself.
+func callTool(tool mcpTool, args json.RawMessage) map[string]interface{} {
+	if len(args) == 0 || string(args) == "null" {
+		args = json.RawMessage("{}")
+	}
+	value, err := tool.call(args)
+	if err != nil {
+		return map[string]interface{}{
+			"content": []map[string]string{{"type": "text", "text": err.Error()}},
+			"isError": true,
+		}
+	}
+	text, _ := json.MarshalIndent(value, "", "  ")
+	return map[string]interface{}{
+		"content":           []map[string]string{{"type": "text", "text": string(text)}},
+		"structuredContent": value,
+		"isError":           false,
+	}
+}
+
+// decodeArgs decodes tool arguments, rejecting unknown ones
+func decodeArgs(args json.RawMessage, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func objectSchema(properties map[string]interface{}, required ...string) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func stringProp(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+// mcpTools are the tools for the workspace with configuration cfg
+func mcpTools(cfg *config.Config) []mcpTool {
+	// stdout carries the protocol; the messages tools print go to stderr
+	log := os.Stderr
+	idProp := stringProp("Work item ID, e.g. 012")
+	return []mcpTool{
+		{
+			Name:        "list_items",
+			Description: "List the work items outside the archive, optionally filtered.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"status":   stringProp("Only items with this status"),
+				"assigned": stringProp("Only items assigned to this person"),
+				"tag":      stringProp("Only items with this tag"),
+				"kind":     stringProp("Only items of this kind, e.g. task or issue"),
+			}),
+			call: func(args json.RawMessage) (interface{}, error) {
+				var a struct{ Status, Assigned, Tag, Kind string }
+				if err := decodeArgs(args, &a); err != nil {
+					return nil, err
+				}
+				items, err := listItems(cfg, itemFilter{Assigned: a.Assigned, Tag: a.Tag, Kind: a.Kind})
+				if err != nil {
+					return nil, err
+				}
+				list := []itemSummary{}
+				for _, item := range items {
+					if a.Status == "" || item.Status == a.Status {
+						list = append(list, item)
+					}
+				}
+				return map[string]interface{}{"items": list}, nil
+			},
+		},
+		{
+			Name:        "get_item",
+			Description: "Read a work item: its fields, markdown body and full file content.",
+			InputSchema: objectSchema(map[string]interface{}{"id": idProp}, "id"),
+			call: func(args json.RawMessage) (interface{}, error) {
+				var a struct{ ID string }
+				if err := decodeArgs(args, &a); err != nil {
+					return nil, err
+				}
+				return loadItemDetail(cfg, a.ID)
+			},
+		},
+		{
+			Name:        "create_item",
+			Description: "Create a work item from a template, as kira new does.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"kind":   stringProp("Template to use, e.g. task, issue, prd or spike"),
+				"title":  stringProp("Title of the item"),
+				"status": stringProp("Status to create it in; defaults to the configured default"),
+				"inputs": map[string]interface{}{"type": "object", "description": "Values for the template's inputs"},
+				"body":   stringProp("Markdown to use as the body instead of the template's"),
+			}, "kind", "title"),
+			call: func(args json.RawMessage) (interface{}, error) {
+				var a struct {
+					Kind, Title, Status, Body string
+					Inputs                    map[string]interface{}
+				}
+				if err := decodeArgs(args, &a); err != nil {
+					return nil, err
+				}
+				planned, err := planItems(cfg, []itemSpec{{Kind: a.Kind, Title: a.Title, Status: a.Status, Inputs: a.Inputs, Body: a.Body}})
+				if err != nil {
+					return nil, err
+				}
+				if err := writeItems(planned); err != nil {
+					return nil, err
+				}
+				return loadItemDetail(cfg, planned[0].ID)
+			},
+		},
+		{
+			Name:        "update_fields",
+			Description: "Set front matter fields of a work item; null removes a field. Setting status moves the item.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id":     idProp,
+				"fields": map[string]interface{}{"type": "object", "description": "Fields to set, e.g. {\"assigned\": \"alice\", \"tags\": [\"ui\"]}"},
+			}, "id", "fields"),
+			call: func(args json.RawMessage) (interface{}, error) {
+				var a struct {
+					ID     string
+					Fields map[string]interface{}
+				}
+				if err := decodeArgs(args, &a); err != nil {
+					return nil, err
+				}
+				if err := updateItemFields(cfg, log, a.ID, a.Fields); err != nil {
+					return nil, err
+				}
+				return loadItemDetail(cfg, a.ID)
+			},
+		},
+		{
+			Name:        "move_item",
+			Description: "Move a work item to another status folder, as kira move does.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id":     idProp,
+				"status": map[string]interface{}{"type": "string", "description": "Target status", "enum": sortedStatuses(cfg)},
+			}, "id", "status"),
+			call: func(args json.RawMessage) (interface{}, error) {
+				var a struct{ ID, Status string }
+				if err := decodeArgs(args, &a); err != nil {
+					return nil, err
+				}
+				if err := moveItem(cfg, log, a.ID, a.Status); err != nil {
+					return nil, err
+				}
+				return loadItemDetail(cfg, a.ID)
+			},
+		},
+		{
+			Name:        "add_comment",
+			Description: "Add a timestamped comment to the Comments section of a work item.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"id":     idProp,
+				"text":   stringProp("Comment text"),
+				"author": stringProp("Who is commenting"),
+			}, "id", "text"),
+			call: func(args json.RawMessage) (interface{}, error) {
+				var a struct{ ID, Text, Author string }
+				if err := decodeArgs(args, &a); err != nil {
+					return nil, err
+				}
+				if err := addItemComment(cfg, a.ID, a.Author, a.Text); err != nil {
+					return nil, err
+				}
+				return loadItemDetail(cfg, a.ID)
+			},
+		},
+		{
+			Name:        "claim_next",
+			Description: "Assign the first unassigned work item (lowest ID) to assignee and move it to doing. Fails while doing is at its WIP limit from kira.yml.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"assignee": stringProp("Who is taking the item"),
+				"from":     stringProp("Status to claim from; defaults to todo"),
+				"tag":      stringProp("Only claim items with this tag"),
+				"kind":     stringProp("Only claim items of this kind"),
+			}, "assignee"),
+			call: func(args json.RawMessage) (interface{}, error) {
+				var a struct{ Assignee, From, Tag, Kind string }
+				if err := decodeArgs(args, &a); err != nil {
+					return nil, err
+				}
+				id, err := claimNextItem(cfg, log, a.Assignee, a.From, itemFilter{Tag: a.Tag, Kind: a.Kind})
+				if err != nil {
+					return nil, err
+				}
+				return loadItemDetail(cfg, id)
+			},
+		},
+		{
+			Name:        "lint",
+			Description: "Validate every work item, as kira lint does.",
+			InputSchema: objectSchema(map[string]interface{}{}),
+			call: func(args json.RawMessage) (interface{}, error) {
+				if err := decodeArgs(args, &struct{}{}); err != nil {
+					return nil, err
+				}
+				issues, err := lintIssues(cfg)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]interface{}{"valid": len(issues) == 0, "issues": issues}, nil
+			},
+		},
+		{
+			Name:        "save",
+			Description: "Commit the work item changes to git, as kira save does. Fails while lint reports issues.",
+			InputSchema: objectSchema(map[string]interface{}{
+				"message": stringProp("Commit message; describes the changes when omitted"),
+			}),
+			call: func(args json.RawMessage) (interface{}, error) {
+				var a struct{ Message string }
+				if err := decodeArgs(args, &a); err != nil {
+					return nil, err
+				}
+				return saveForAgent(cfg, log, a.Message)
+			},
+		},
+	}
+}
+
+// saveForAgent runs kira save and reports the commit it made, if any. Lint
+// issues are returned in the error, as kira save only prints them.
+func saveForAgent(cfg *config.Config, log io.Writer, message string) (interface{}, error) {
+	issues, err := lintIssues(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		var lines []string
+		for _, issue := range issues {
+			lines = append(lines, fmt.Sprintf("%s: %s", issue.File, issue.Message))
+		}
+		return nil, fmt.Errorf("validation failed - fix errors before saving:\n%s", strings.Join(lines, "\n"))
+	}
+	before, _ := runGit("rev-parse", "HEAD")
+	if err := saveWorkItems(cfg, log, message, saveOptions{}); err != nil {
+		return nil, err
+	}
+	after, _ := runGit("rev-parse", "HEAD")
+	result := map[string]interface{}{"saved": before != after}
+	if before != after {
+		result["commit"] = strings.TrimSpace(after)
+	}
+	return result, nil
+}
+
+// mcpItemURI prefixes work item IDs in resource URIs
+const mcpItemURI = "kira://items/"
+
+const mcpConfigURI = "kira://config"
+
+func mcpResources(cfg *config.Config) ([]map[string]string, error) {
+	items, err := listItems(cfg, itemFilter{})
+	if err != nil {
+		return nil, err
+	}
+	resources := []map[string]string{{
+		"uri":         mcpConfigURI,
+		"name":        "kira.yml",
+		"description": "Workspace configuration: status folders, templates and validation rules",
+		"mimeType":    "application/yaml",
+	}}
+	for _, item := range items {
+		resources = append(resources, map[string]string{
+			"uri":         mcpItemURI + item.ID,
+			"name":        item.ID + " " + item.Title,
+			"description": fmt.Sprintf("%s in %s", item.Kind, item.Status),
+			"mimeType":    "text/markdown",
+		})
+	}
+	return resources, nil
+}
+
+func readMCPResource(cfg *config.Config, uri string) (interface{}, *rpcError) {
+	var path, mimeType string
+	switch {
+	case uri == mcpConfigURI:
+		path, mimeType = config.ConfigPath(), "application/yaml"
+	case strings.HasPrefix(uri, mcpItemURI):
+		found, err := findItemPath(cfg, strings.TrimPrefix(uri, mcpItemURI))
+		if err != nil {
+			return nil, &rpcError{mcpResourceNotFound, fmt.Sprintf("resource not found: %s", uri)}
+		}
+		path, mimeType = found, "text/markdown"
+	default:
+		return nil, &rpcError{mcpResourceNotFound, fmt.Sprintf("resource not found: %s", uri)}
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &rpcError{mcpResourceNotFound, fmt.Sprintf("resource not found: %s", uri)}
+	}
+	return map[string]interface{}{"contents": []map[string]string{{"uri": uri, "mimeType": mimeType, "text": string(content)}}}, nil
+}
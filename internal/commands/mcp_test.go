@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestMCPServer(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	requests := []string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"create_item","arguments":{"kind":"task","title":"Login page","status":"todo"}}}`,
+		`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"claim_next","arguments":{"assignee":"agent"}}}`,
+		`{"jsonrpc":"2.0","id":5,"method":"tools/call","params":{"name":"claim_next","arguments":{"assignee":"agent"}}}`,
+		`{"jsonrpc":"2.0","id":6,"method":"tools/call","params":{"name":"list_items","arguments":{"status":"doing"}}}`,
+		`{"jsonrpc":"2.0","id":7,"method":"tools/call","params":{"name":"save","arguments":{}}}`,
+		`{"jsonrpc":"2.0","id":8,"method":"resources/list"}`,
+		`{"jsonrpc":"2.0","id":9,"method":"resources/read","params":{"uri":"kira://items/001"}}`,
+		`{"jsonrpc":"2.0","id":10,"method":"tools/call","params":{"name":"get_item","arguments":{"id":"001","extra":true}}}`,
+		`{"jsonrpc":"2.0","id":11,"method":"nope"}`,
+		`not json`,
+	}
+	var out bytes.Buffer
+	require.NoError(t, serveMCP(cfg, strings.NewReader(strings.Join(requests, "\n")+"\n"), &out))
+
+	responses := make(map[string]map[string]interface{})
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var resp map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &resp), line)
+		responses[string(mustJSON(t, resp["id"]))] = resp
+	}
+	assert.Len(t, responses, 12, "the notification gets no response")
+	result := func(id string) map[string]interface{} {
+		require.Contains(t, responses, id)
+		require.Nil(t, responses[id]["error"], id)
+		return responses[id]["result"].(map[string]interface{})
+	}
+
+	assert.Equal(t, "2025-03-26", result("1")["protocolVersion"])
+	tools := result("2")["tools"].([]interface{})
+	require.Len(t, tools, 9)
+	for _, tool := range tools {
+		schema := tool.(map[string]interface{})["inputSchema"].(map[string]interface{})
+		assert.Equal(t, "object", schema["type"])
+	}
+
+	created := result("3")["structuredContent"].(map[string]interface{})
+	assert.Equal(t, "001", created["id"])
+	assert.Equal(t, false, result("3")["isError"])
+
+	claimed := result("4")["structuredContent"].(map[string]interface{})
+	assert.Equal(t, "doing", claimed["status"])
+	assert.Equal(t, "agent", claimed["assigned"])
+	assert.Equal(t, true, result("5")["isError"], "nothing left to claim")
+
+	items := result("6")["structuredContent"].(map[string]interface{})["items"].([]interface{})
+	require.Len(t, items, 1)
+	assert.Equal(t, "001", items[0].(map[string]interface{})["id"])
+
+	assert.Equal(t, true, result("7")["structuredContent"].(map[string]interface{})["saved"])
+	status, err := runGit("status", "--porcelain", ".work")
+	require.NoError(t, err)
+	assert.Empty(t, status)
+
+	resources := result("8")["resources"].([]interface{})
+	require.Len(t, resources, 2)
+	assert.Equal(t, "kira://config", resources[0].(map[string]interface{})["uri"])
+	contents := result("9")["contents"].([]interface{})
+	assert.Contains(t, contents[0].(map[string]interface{})["text"], "assigned: agent")
+
+	assert.Equal(t, true, result("10")["isError"], "unknown arguments are rejected")
+	assert.Equal(t, float64(rpcMethodNotFound), responses["11"]["error"].(map[string]interface{})["code"])
+	assert.Equal(t, float64(rpcParseError), responses["null"]["error"].(map[string]interface{})["code"])
+}
+
+func mustJSON(t *testing.T, v interface{}) []byte {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"kira/internal/merge"
+)
+
+// mergeDriverName is the merge driver registered in git config
+const mergeDriverName = "kira"
+
+// mergeAttributes routes work item files to the kira merge driver
+const mergeAttributes = ".work/**/*.md merge=" + mergeDriverName
+
+var gitCmd = &cobra.Command{
+	Use:   "git",
+	Short: "Integrate kira with git",
+}
+
+var gitInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Register the kira merge driver for work item files",
+	Long: `Registers 'kira merge-driver' in the repository's git config and routes
+.work/**/*.md to it in .gitattributes. Work items edited on two branches
+are then merged field by field and section by section instead of line by
+line. Safe to run more than once.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		return installMergeDriver()
+	},
+}
+
+var mergeDriverCmd = &cobra.Command{
+	Use:   "merge-driver <base> <ours> <theirs> [path]",
+	Short: "Three-way merge of a work item file (run by git)",
+	Long: `Merges work item files for git, which runs it as
+'kira merge-driver %O %A %B %P' once 'kira git install' has registered it.
+The result is written to <ours>.
+
+Front matter is merged field by field and the body section by section.
+updated keeps the later timestamp and list fields such as tags combine the
+changes of both sides. Conflict markers are left only where the same field
+or section changed differently on both sides. Files without front matter
+get git's usual line-based merge.`,
+	Args:         cobra.RangeArgs(3, 4),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[1]
+		if len(args) == 4 {
+			path = args[3]
+		}
+		return mergeWorkItemFiles(args[0], args[1], args[2], path)
+	},
+}
+
+func init() {
+	gitCmd.AddCommand(gitInstallCmd)
+}
+
+func installMergeDriver() error {
+	if !isGitRepo() {
+		return fmt.Errorf("not a git repository: run 'git init' first")
+	}
+	if _, err := runGit("config", "merge."+mergeDriverName+".name", "kira work item merge"); err != nil {
+		return fmt.Errorf("failed to register merge driver: %w", err)
+	}
+	// Git runs the driver without kira's PATH, e.g. from a GUI client, so
+	// register this binary by its absolute path
+	exe, err := kiraExecutable()
+	if err != nil {
+		return err
+	}
+	if _, err := runGit("config", "merge."+mergeDriverName+".driver", shellQuote(exe)+" merge-driver %O %A %B %P"); err != nil {
+		return fmt.Errorf("failed to register merge driver: %w", err)
+	}
+
+	content, err := os.ReadFile(".gitattributes")
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.TrimSpace(line) == mergeAttributes {
+			fmt.Println("Merge driver registered; .gitattributes already routes work items to it.")
+			return nil
+		}
+	}
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		content = append(content, '\n')
+	}
+	content = append(content, mergeAttributes+"\n"...)
+	if err := os.WriteFile(".gitattributes", content, 0644); err != nil {
+		return fmt.Errorf("failed to write .gitattributes: %w", err)
+	}
+	fmt.Println("Merge driver registered and added to .gitattributes. Commit .gitattributes to share it.")
+	return nil
+}
+
+// kiraExecutable is the absolute path of the running kira binary, for git to
+// run where kira is not on the PATH
+func kiraExecutable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the kira executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	return exe, nil
+}
+
+// shellQuote quotes s for sh, which git runs merge drivers and hooks with
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// mergeWorkItemFiles merges the three versions git hands a merge driver and
+// writes the result over ours. An error means conflicts were left in ours.
+func mergeWorkItemFiles(basePath, oursPath, theirsPath, path string) error {
+	var content [3]string
+	for i, p := range []string{basePath, oursPath, theirsPath} {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		content[i] = string(data)
+	}
+
+	result, err := merge.WorkItem(content[0], content[1], content[2])
+	if errors.Is(err, merge.ErrNoFrontMatter) {
+		return mergeLines(basePath, oursPath, theirsPath, path)
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(oursPath, []byte(result.Content), 0644); err != nil {
+		return fmt.Errorf("failed to write merge result: %w", err)
+	}
+	if len(result.Conflicts) > 0 {
+		return fmt.Errorf("conflicts in %s: %s", path, strings.Join(result.Conflicts, ", "))
+	}
+	return nil
+}
+
+// mergeLines falls back to git's line-based merge
+func mergeLines(basePath, oursPath, theirsPath, path string) error {
+	cmd := exec.Command("git", "merge-file", "-L", "ours", "-L", "base", "-L", "theirs", oursPath, basePath, theirsPath)
+	cmd.Stderr = os.Stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() < 128 {
+		return fmt.Errorf("conflicts in %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to merge %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallMergeDriver(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+
+	require.NoError(t, os.WriteFile(".gitattributes", []byte("*.png binary"), 0644))
+	require.NoError(t, installMergeDriver())
+	require.NoError(t, installMergeDriver())
+
+	attrs, err := os.ReadFile(".gitattributes")
+	require.NoError(t, err)
+	assert.Equal(t, "*.png binary\n"+mergeAttributes+"\n", string(attrs))
+
+	driver, err := runGit("config", "--get", "merge.kira.driver")
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(driver), "' merge-driver %O %A %B %P"), driver)
+	assert.True(t, strings.HasPrefix(driver, "'/"), "registered by absolute path: %s", driver)
+
+	attr, err := runGit("check-attr", "merge", "--", ".work/1_todo/001-x.task.md")
+	require.NoError(t, err)
+	assert.Contains(t, attr, "merge: kira")
+}
+
+func TestMergeWorkItemFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := dir + "/" + name
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	base := write("base", "---\nid: 001\nstatus: todo\nupdated: 2025-01-01T00:00:00Z\n---\n# X\n")
+	ours := write("ours", "---\nid: 001\nstatus: doing\nupdated: 2025-01-02T00:00:00Z\n---\n# X\n")
+	theirs := write("theirs", "---\nid: 001\nstatus: todo\nupdated: 2025-01-03T00:00:00Z\n---\n# X\n\n## Notes\nHi\n")
+
+	require.NoError(t, mergeWorkItemFiles(base, ours, theirs, "001-x.task.md"))
+	merged, err := os.ReadFile(ours)
+	require.NoError(t, err)
+	assert.Equal(t, "---\nid: 001\nstatus: doing\nupdated: 2025-01-03T00:00:00Z\n---\n# X\n\n## Notes\nHi\n", string(merged))
+
+	// Without front matter git's line merge is used
+	base = write("base", "a\nb\nc\n")
+	ours = write("ours", "A\nb\nc\n")
+	theirs = write("theirs", "a\nb\nC\n")
+	require.NoError(t, mergeWorkItemFiles(base, ours, theirs, "IDEAS.md"))
+	merged, err = os.ReadFile(ours)
+	require.NoError(t, err)
+	assert.Equal(t, "A\nb\nC\n", string(merged))
+}
+
+func TestMergeDriverWithoutPath(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+
+	// Build kira as in the integration test; git install registers it by path
+	_, thisFile, _, _ := runtime.Caller(0)
+	repoRoot := filepath.Clean(filepath.Join(filepath.Dir(thisFile), "..", ".."))
+	kira := filepath.Join(t.TempDir(), "kira")
+	build := exec.Command("go", "build", "-o", kira, "./cmd/kira")
+	build.Dir = repoRoot
+	output, err := build.CombinedOutput()
+	require.NoError(t, err, "build failed: %s", string(output))
+	output, err = exec.Command(kira, "git", "install").CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	git := func(args ...string) {
+		_, err := runGit(args...)
+		require.NoError(t, err)
+	}
+	write := func(content string) {
+		require.NoError(t, os.WriteFile(".work/1_todo/001-x.task.md", []byte(content), 0644))
+	}
+	write("---\nid: 001\ntitle: X\nstatus: todo\ntags: [a]\n---\n# X\n")
+	git("add", "-A")
+	git("commit", "-q", "-m", "item")
+	git("switch", "-q", "-c", "other")
+	write("---\nid: 001\ntitle: X\nstatus: doing\ntags: [a]\n---\n# X\n")
+	git("commit", "-q", "-am", "status")
+	git("switch", "-q", "-")
+	write("---\nid: 001\ntitle: X\nstatus: todo\ntags: [a, b]\n---\n# X\n")
+	git("commit", "-q", "-am", "tags")
+
+	// Merge with an empty PATH, as a GUI client might run git
+	gitPath, err := exec.LookPath("git")
+	require.NoError(t, err)
+	merge := exec.Command(gitPath, "merge", "-q", "--no-edit", "other")
+	merge.Env = append(os.Environ(), "PATH=")
+	output, err = merge.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	merged, err := os.ReadFile(".work/1_todo/001-x.task.md")
+	require.NoError(t, err)
+	assert.Equal(t, "---\nid: 001\ntitle: X\nstatus: doing\ntags: [a, b]\n---\n# X\n", string(merged))
+}
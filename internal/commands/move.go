@@ -1,12 +1,10 @@
 package commands
 
 import (
-	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
-	"strconv"
-	"strings"
 
 	"github.com/spf13/cobra"
 	"kira/internal/config"
@@ -33,11 +31,13 @@ var moveCmd = &cobra.Command{
 			targetStatus = args[1]
 		}
 
-		return moveWorkItem(cfg, workItemID, targetStatus)
+		return moveWorkItem(cfg, os.Stdout, workItemID, targetStatus)
 	},
 }
 
-func moveWorkItem(cfg *config.Config, workItemID, targetStatus string) error {
+// moveWorkItem moves a work item to targetStatus, prompting for it when
+// empty, and reports the move on out
+func moveWorkItem(cfg *config.Config, out io.Writer, workItemID, targetStatus string) error {
 	// Find the work item file
 	workItemPath, err := findWorkItemFile(workItemID)
 	if err != nil {
@@ -74,34 +74,12 @@ func moveWorkItem(cfg *config.Config, workItemID, targetStatus string) error {
 		return fmt.Errorf("failed to update work item status: %w", err)
 	}
 
-	fmt.Printf("Moved work item %s to %s\n", workItemID, targetStatus)
+	fmt.Fprintf(out, "Moved work item %s to %s\n", workItemID, targetStatus)
 	return nil
 }
 
 
 func selectTargetStatus(cfg *config.Config) (string, error) {
-	fmt.Println("Available statuses:")
-	var statuses []string
-	for status := range cfg.StatusFolders {
-		statuses = append(statuses, status)
-	}
-
-	for i, status := range statuses {
-		fmt.Printf("%d. %s\n", i+1, status)
-	}
-
-	fmt.Print("Select target status (number): ")
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
-	}
-
-	choice, err := strconv.Atoi(strings.TrimSpace(input))
-	if err != nil || choice < 1 || choice > len(statuses) {
-		return "", fmt.Errorf("invalid status selection")
-	}
-
-	return statuses[choice-1], nil
+	return prompts.selectOne("target status", sortedStatuses(cfg), false)
 }
 
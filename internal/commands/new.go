@@ -1,10 +1,10 @@
 package commands
 
 import (
-	"bufio"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,6 +34,29 @@ All arguments are optional - will prompt for selection if not provided.`,
 		ignoreInput, _ := cmd.Flags().GetBool("ignore-input")
 		inputValues, _ := cmd.Flags().GetStringToString("input")
 		helpInputs, _ := cmd.Flags().GetBool("help-inputs")
+		from, _ := cmd.Flags().GetString("from")
+		fromJSON, _ := cmd.Flags().GetString("from-json")
+
+		switch {
+		case from != "" && fromJSON != "":
+			return fmt.Errorf("--from and --from-json cannot be combined")
+		case from != "":
+			var kind string
+			if len(args) > 0 {
+				kind = args[0]
+			}
+			return adoptWorkItem(cfg, from, kind)
+		case fromJSON != "":
+			data, err := readSource(fromJSON)
+			if err != nil {
+				return err
+			}
+			specs, err := parseJSONItemSpecs(data)
+			if err != nil {
+				return fmt.Errorf("failed to parse item JSON: %w", err)
+			}
+			return createItems(cfg, specs, false)
+		}
 
 		return createWorkItem(cfg, args, ignoreInput, inputValues, helpInputs)
 	},
@@ -43,6 +66,8 @@ func init() {
 	newCmd.Flags().Bool("ignore-input", false, "Skip interactive input prompts")
     newCmd.Flags().StringToStringP("input", "i", nil, "Provide input values directly (e.g., --input due=2025-10-01)")
 	newCmd.Flags().Bool("help-inputs", false, "List available input variables for a template")
+	newCmd.Flags().String("from", "", "Adopt a hand-written markdown file as a new work item")
+	newCmd.Flags().String("from-json", "", "Create work items from a JSON spec or array of specs (- for stdin)")
 }
 
 func createWorkItem(cfg *config.Config, args []string, ignoreInput bool, inputValues map[string]string, helpInputs bool) error {
@@ -79,7 +104,7 @@ func createWorkItem(cfg *config.Config, args []string, ignoreInput bool, inputVa
 	// Get title if not provided
 	if title == "" && !ignoreInput {
 		var err error
-		title, err = promptString("Enter work item title: ")
+		title, err = prompts.line("Enter work item title: ")
 		if err != nil {
 			return err
 		}
@@ -101,6 +126,7 @@ func createWorkItem(cfg *config.Config, args []string, ignoreInput bool, inputVa
 	inputs["id"] = nextID
 	inputs["title"] = title
 	inputs["status"] = status
+	inputs["kind"] = template
 	inputs["created"] = time.Now().Format("2006-01-02")
 
 	// Add any provided input values
@@ -108,15 +134,15 @@ func createWorkItem(cfg *config.Config, args []string, ignoreInput bool, inputVa
 		inputs[k] = v
 	}
 
-	// Get template inputs and prompt for missing ones
-	if !ignoreInput {
-		templatePath := filepath.Join(".work", cfg.Templates[template])
-		templateInputs, err := templates.GetTemplateInputs(templatePath)
-		if err != nil {
-			return fmt.Errorf("failed to get template inputs: %w", err)
-		}
+	templatePath := filepath.Join(".work", cfg.Templates[template])
+	tmpl, err := templates.LoadTemplate(templatePath)
+	if err != nil {
+		return fmt.Errorf("failed to get template inputs: %w", err)
+	}
 
-		for _, input := range templateInputs {
+	// Prompt for template inputs that were not provided
+	if !ignoreInput {
+		for _, input := range tmpl.Inputs {
 			if _, exists := inputs[input.Name]; !exists {
 				value, err := promptForInput(input)
 				if err != nil {
@@ -127,15 +153,32 @@ func createWorkItem(cfg *config.Config, args []string, ignoreInput bool, inputVa
 		}
 	}
 
+	if err := completeInputs(tmpl, template, inputs, ignoreInput); err != nil {
+		return err
+	}
+
+	// Let the user look over the answers before anything is written
+	if !ignoreInput && prompts.tty {
+		ok, err := reviewWorkItem(cfg, tmpl, inputs)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("work item not created")
+		}
+		title, status = inputs["title"], inputs["status"]
+	}
+
 	// Generate work item content
-	templatePath := filepath.Join(".work", cfg.Templates[template])
-	content, err := templates.ProcessTemplate(templatePath, inputs)
+	renderOpts := templates.DefaultRenderOptions()
+	renderOpts.NextID = validation.GetNextID
+	content, err := tmpl.Render(inputs, renderOpts)
 	if err != nil {
 		return fmt.Errorf("failed to process template: %w", err)
 	}
 
 	// Create filename
-	filename := fmt.Sprintf("%s-%s.%s.md", nextID, kebabCase(title), template)
+	filename := workItemFilename(nextID, title, template)
 	statusFolder := cfg.StatusFolders[status]
 	filePath := filepath.Join(".work", statusFolder, filename)
 
@@ -149,55 +192,51 @@ func createWorkItem(cfg *config.Config, args []string, ignoreInput bool, inputVa
 }
 
 func selectTemplate(cfg *config.Config) (string, error) {
-	fmt.Println("Available templates:")
-	var templates []string
-	for template := range cfg.Templates {
-		templates = append(templates, template)
-	}
-
-	for i, template := range templates {
-		fmt.Printf("%d. %s\n", i+1, template)
-	}
-
-	fmt.Print("Select template (number): ")
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
-	}
-
-	choice, err := strconv.Atoi(strings.TrimSpace(input))
-	if err != nil || choice < 1 || choice > len(templates) {
-		return "", fmt.Errorf("invalid template selection")
-	}
-
-	return templates[choice-1], nil
+	return prompts.selectOne("template", sortedKinds(cfg), false)
 }
 
 func selectStatus(cfg *config.Config) (string, error) {
-	fmt.Println("Available statuses:")
+	return prompts.selectOne("status", sortedStatuses(cfg), false)
+}
+
+// sortedStatuses returns the statuses in the order of their folders, which
+// carry the workflow order (backlog, todo, doing, review, done)
+func sortedStatuses(cfg *config.Config) []string {
 	var statuses []string
 	for status := range cfg.StatusFolders {
 		statuses = append(statuses, status)
 	}
+	sort.Slice(statuses, func(i, j int) bool {
+		fi, fj := cfg.StatusFolders[statuses[i]], cfg.StatusFolders[statuses[j]]
+		if fi != fj {
+			return folderBefore(fi, fj)
+		}
+		return statuses[i] < statuses[j]
+	})
+	return statuses
+}
 
-	for i, status := range statuses {
-		fmt.Printf("%d. %s\n", i+1, status)
-	}
-
-	fmt.Print("Select status (number): ")
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
+// folderBefore orders status folders by their numeric prefix, so 10_done
+// comes after 2_doing; folders without one sort after, by name
+func folderBefore(a, b string) bool {
+	na, aok := folderNumber(a)
+	nb, bok := folderNumber(b)
+	switch {
+	case aok && bok && na != nb:
+		return na < nb
+	case aok != bok:
+		return aok
 	}
+	return a < b
+}
 
-	choice, err := strconv.Atoi(strings.TrimSpace(input))
-	if err != nil || choice < 1 || choice > len(statuses) {
-		return "", fmt.Errorf("invalid status selection")
+func folderNumber(folder string) (int, bool) {
+	digits := len(folder) - len(strings.TrimLeft(folder, "0123456789"))
+	if digits == 0 {
+		return 0, false
 	}
-
-	return statuses[choice-1], nil
+	n, err := strconv.Atoi(folder[:digits])
+	return n, err == nil
 }
 
 func showTemplateInputs(cfg *config.Config, template string) error {
@@ -218,87 +257,133 @@ func showTemplateInputs(cfg *config.Config, template string) error {
 	return nil
 }
 
-func promptForInput(input templates.Input) (string, error) {
-	prompt := fmt.Sprintf("Enter %s (%s): ", input.Name, input.Description)
-
-	switch input.Type {
-	case templates.InputString:
-		if len(input.Options) > 0 {
-			return promptStringOptions(prompt, input.Options)
+// completeInputs fills in defaults and normalizes and validates the values.
+// With requireAll, missing required inputs are an error listing the --input
+// flags still needed.
+func completeInputs(tmpl *templates.Template, kind string, inputs map[string]string, requireAll bool) error {
+	tmpl.ApplyDefaults(inputs)
+	if err := tmpl.NormalizeValues(inputs, time.Now()); err != nil {
+		return err
+	}
+	if requireAll {
+		if missing := tmpl.MissingRequired(inputs); len(missing) > 0 {
+			return missingInputsError(kind, missing)
 		}
-		return promptString(prompt)
-	case templates.InputNumber:
-		return promptNumber(prompt)
-	case templates.InputDateTime:
-		return promptDateTime(prompt, input.DateFormat)
-	default:
-		return promptString(prompt)
 	}
+	return tmpl.ValidateValues(inputs)
 }
 
-func promptString(prompt string) (string, error) {
-	fmt.Print(prompt)
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(input), nil
+// workItemFilename is the file name of a work item: <id>-<title>.<kind>.md
+func workItemFilename(id, title, kind string) string {
+	return fmt.Sprintf("%s-%s.%s.md", id, kebabCase(title), kind)
 }
 
-func promptStringOptions(prompt string, options []string) (string, error) {
-	fmt.Println(prompt)
-	for i, option := range options {
-		fmt.Printf("%d. %s\n", i+1, option)
+// missingInputsError lists the required inputs that must be passed with
+// --input when prompting is disabled
+func missingInputsError(template string, missing []templates.Input) error {
+	var lines []string
+	for _, input := range missing {
+		lines = append(lines, fmt.Sprintf("  --input %s=<%s>  %s", input.Name, input.Type, input.Description))
 	}
-	fmt.Print("Select option (number): ")
+	return fmt.Errorf("missing required inputs for template '%s':\n%s", template, strings.Join(lines, "\n"))
+}
 
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
+// promptForInput asks for one input. Invalid answers are reported and asked
+// again on a terminal.
+func promptForInput(input templates.Input) (string, error) {
+	label := fmt.Sprintf("%s (%s)", input.Name, input.Description)
+	if input.Default != "" {
+		label = fmt.Sprintf("%s [%s]", label, input.Default)
 	}
 
-	choice, err := strconv.Atoi(strings.TrimSpace(input))
-	if err != nil || choice < 1 || choice > len(options) {
-		return "", fmt.Errorf("invalid option selection")
+	read := func() (string, error) {
+		switch {
+		case len(input.Options) > 0 && input.IsList():
+			selected, err := prompts.selectMany(label, input.Options, nil)
+			return strings.Join(selected, ","), err
+		case len(input.Options) > 0:
+			return prompts.selectOne(label, input.Options, true)
+		case input.IsList():
+			return prompts.line(fmt.Sprintf("Enter %s (comma separated): ", label))
+		case input.Type == templates.InputDateTime:
+			return prompts.line(fmt.Sprintf("Enter %s (format: %s, or +3d, next friday, eow): ", label, input.DateFormat))
+		default:
+			return prompts.text(fmt.Sprintf("Enter %s: ", label), input.Multiline)
+		}
 	}
-
-	return options[choice-1], nil
+	return prompts.ask(read, func(value string) (string, error) {
+		if value == "" {
+			value = input.Default
+		}
+		value, err := input.Normalize(value, time.Now())
+		if err != nil {
+			return "", err
+		}
+		return value, input.Validate(value)
+	})
 }
 
-func promptNumber(prompt string) (string, error) {
-	fmt.Print(prompt)
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
-	}
+// reviewWorkItem shows the collected values and lets the user change any of
+// them before the item is written. It returns false when the user cancels.
+func reviewWorkItem(cfg *config.Config, tmpl *templates.Template, inputs map[string]string) (bool, error) {
+	for {
+		fmt.Fprintln(prompts.out, "\nReview work item:")
+		fields := []string{"title", "status"}
+		for _, input := range tmpl.Inputs {
+			fields = append(fields, input.Name)
+		}
+		for _, name := range fields {
+			value := inputs[name]
+			if i := strings.Index(value, "\n"); i >= 0 {
+				value = value[:i] + " …"
+			}
+			fmt.Fprintf(prompts.out, "  %-16s %s\n", name+":", value)
+		}
 
-	// Validate it's a number
-	_, err = strconv.Atoi(strings.TrimSpace(input))
-	if err != nil {
-		return "", fmt.Errorf("invalid number: %v", err)
+		choice, err := prompts.line("[c]reate, [e]dit a field or [a]bort? [c]: ")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(choice) {
+		case "", "c", "create":
+			return true, nil
+		case "a", "abort":
+			return false, nil
+		case "e", "edit":
+			name, err := prompts.selectOne("field", fields, false)
+			if err != nil {
+				return false, err
+			}
+			if err := editReviewField(cfg, tmpl, inputs, name); err != nil {
+				return false, err
+			}
+		default:
+			fmt.Fprintf(prompts.out, "  ! unknown choice %q\n", choice)
+		}
 	}
-
-	return strings.TrimSpace(input), nil
 }
 
-func promptDateTime(prompt, format string) (string, error) {
-	fmt.Printf("%s (format: %s): ", prompt, format)
-	reader := bufio.NewReader(os.Stdin)
-	input, err := reader.ReadString('\n')
-	if err != nil {
-		return "", err
+func editReviewField(cfg *config.Config, tmpl *templates.Template, inputs map[string]string, name string) error {
+	var value string
+	var err error
+	switch name {
+	case "title":
+		value, err = prompts.line("Enter work item title: ")
+	case "status":
+		value, err = selectStatus(cfg)
+	default:
+		for _, input := range tmpl.Inputs {
+			if input.Name == name {
+				value, err = promptForInput(input)
+				break
+			}
+		}
 	}
-
-	// Validate date format
-	_, err = time.Parse(format, strings.TrimSpace(input))
 	if err != nil {
-		return "", fmt.Errorf("invalid date format: %v", err)
+		return err
 	}
-
-	return strings.TrimSpace(input), nil
+	inputs[name] = value
+	return nil
 }
 
 func kebabCase(s string) string {
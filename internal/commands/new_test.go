@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestCreateWorkItemRequiredInputs(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, initializeWorkspace(tmpDir))
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+
+	template := `---
+template:
+  inputs:
+    - name: estimate
+      type: number
+      required: true
+      description: Estimate in days
+---
+---
+id: {{ .id }}
+title: {{ .title }}
+status: {{ .status }}
+kind: task
+created: {{ .created }}
+estimate: {{ .estimate }}
+---
+`
+	require.NoError(t, os.WriteFile(".work/templates/template.task.md", []byte(template), 0644))
+	cfg := &config.DefaultConfig
+
+	err := createWorkItem(cfg, []string{"task", "Sized task"}, true, nil, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--input estimate=<number>")
+
+	err = createWorkItem(cfg, []string{"task", "Sized task"}, true, map[string]string{"estimate": "x"}, false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a number")
+
+	require.NoError(t, createWorkItem(cfg, []string{"task", "Sized task"}, true, map[string]string{"estimate": "2"}, false))
+	matches, _ := filepath.Glob(".work/0_backlog/*-sized-task.task.md")
+	require.Len(t, matches, 1)
+	content, err := os.ReadFile(matches[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "estimate: 2")
+}
+
+func TestCreateWorkItemPipedAnswers(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, initializeWorkspace(tmpDir))
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+
+	template := `---
+template:
+  inputs:
+    - name: labels
+      type: strings
+      options: [ui, api, docs]
+      description: Labels
+---
+---
+id: {{ .id }}
+title: {{ .title }}
+status: {{ .status }}
+kind: task
+created: {{ .created }}
+priority: {{ choose "priority" "Priority" "low" "medium" "high" }}
+labels: {{ .labels }}
+---
+{{ prompt "description" "What needs to be done?" }}
+`
+	require.NoError(t, os.WriteFile(".work/templates/template.task.md", []byte(template), 0644))
+	cfg := &config.DefaultConfig
+
+	var out bytes.Buffer
+	saved := prompts
+	prompts = &prompter{in: bufio.NewReader(strings.NewReader("tas\nPiped task\n1,docs\nmed\nShip it\n")), out: &out}
+	defer func() { prompts = saved }()
+
+	require.NoError(t, createWorkItem(cfg, nil, false, nil, false))
+	matches, _ := filepath.Glob(".work/0_backlog/*-piped-task.task.md")
+	require.Len(t, matches, 1)
+	content, err := os.ReadFile(matches[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "priority: medium")
+	assert.Contains(t, string(content), "labels: [ui, docs]")
+	assert.Contains(t, string(content), "Ship it")
+	assert.Contains(t, out.String(), "1. issue\n2. prd\n3. spike\n4. task\n")
+}
@@ -0,0 +1,449 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// errCancelled is returned when the user aborts a prompt with Ctrl-C or Esc
+var errCancelled = errors.New("cancelled")
+
+// prompter asks the user questions on stdin. A single reader is shared by
+// every prompt so answers piped in one per line are not lost to buffering.
+// On a terminal lists become arrow-key pickers with fuzzy filtering and
+// invalid answers are asked again; otherwise lists are numbered and an
+// invalid answer is an error.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+	tty bool
+}
+
+// prompts is the prompter used by all interactive commands
+var prompts = &prompter{
+	in:  bufio.NewReader(os.Stdin),
+	out: os.Stdout,
+	tty: isTerminal(os.Stdin) && isTerminal(os.Stdout),
+}
+
+// maxVisibleOptions is the height of the picker list before it scrolls
+const maxVisibleOptions = 10
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// line prints label and reads one line of input, trimmed
+func (p *prompter) line(label string) (string, error) {
+	fmt.Fprint(p.out, label)
+	input, err := p.in.ReadString('\n')
+	if err != nil && (err != io.EOF || input == "") {
+		return "", err
+	}
+	return strings.TrimSpace(input), nil
+}
+
+// ask reads an answer with read and checks it with check. On a terminal a
+// rejected answer is reported and asked again.
+func (p *prompter) ask(read func() (string, error), check func(string) (string, error)) (string, error) {
+	for {
+		value, err := read()
+		if err != nil {
+			return "", err
+		}
+		value, err = check(value)
+		if err == nil {
+			return value, nil
+		}
+		if !p.tty {
+			return "", err
+		}
+		fmt.Fprintf(p.out, "  ! %v\n", err)
+	}
+}
+
+// text reads a free-text answer. Multi-line fields accept ":e" to write the
+// answer in $EDITOR instead.
+func (p *prompter) text(label string, multiline bool) (string, error) {
+	if multiline {
+		label = strings.TrimSuffix(label, ": ") + " (:e for editor): "
+	}
+	value, err := p.line(label)
+	if err != nil || !multiline || value != ":e" {
+		return value, err
+	}
+	return editText("")
+}
+
+// confirm asks a yes/no question, answering def on an empty line
+func (p *prompter) confirm(label string, def bool) (bool, error) {
+	hint := "[y/N]"
+	if def {
+		hint = "[Y/n]"
+	}
+	input, err := p.line(fmt.Sprintf("%s %s: ", label, hint))
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(input) {
+	case "":
+		return def, nil
+	case "y", "yes":
+		return true, nil
+	case "n", "no":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid answer %q: expected y or n", input)
+}
+
+// selectOne picks one of options. An empty answer returns "" when
+// allowEmpty is set.
+func (p *prompter) selectOne(label string, options []string, allowEmpty bool) (string, error) {
+	if len(options) == 0 {
+		return "", fmt.Errorf("no %s to choose from", label)
+	}
+	if p.tty {
+		selected, err := p.pick(label, options, nil, false)
+		if err != nil || len(selected) == 0 {
+			return "", err
+		}
+		return options[selected[0]], nil
+	}
+
+	fmt.Fprintf(p.out, "Select %s:\n", label)
+	for i, option := range options {
+		fmt.Fprintf(p.out, "%d. %s\n", i+1, option)
+	}
+	input, err := p.line("Enter number or name: ")
+	if err != nil {
+		return "", err
+	}
+	if input == "" && allowEmpty {
+		return "", nil
+	}
+	idx, err := matchOption(options, input)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s selection: %w", label, err)
+	}
+	return options[idx], nil
+}
+
+// selectMany picks any number of options, starting from the checked ones
+func (p *prompter) selectMany(label string, options []string, checked []bool) ([]string, error) {
+	var indexes []int
+	if p.tty {
+		var err error
+		if indexes, err = p.pick(label, options, checked, true); err != nil {
+			return nil, err
+		}
+	} else {
+		fmt.Fprintf(p.out, "Select %s:\n", label)
+		for i, option := range options {
+			fmt.Fprintf(p.out, "%d. %s\n", i+1, option)
+		}
+		input, err := p.line("Enter numbers or names (e.g. 1,3): ")
+		if err != nil {
+			return nil, err
+		}
+		if indexes, err = matchOptions(options, input); err != nil {
+			return nil, fmt.Errorf("invalid %s selection: %w", label, err)
+		}
+	}
+	var selected []string
+	for _, i := range indexes {
+		selected = append(selected, options[i])
+	}
+	return selected, nil
+}
+
+// matchOption resolves an answer to an option index: a 1-based number, the
+// exact option, or a fuzzy match that leaves a single candidate
+func matchOption(options []string, input string) (int, error) {
+	input = strings.TrimSpace(input)
+	if n, err := strconv.Atoi(input); err == nil {
+		if n < 1 || n > len(options) {
+			return 0, fmt.Errorf("%d is out of range 1-%d", n, len(options))
+		}
+		return n - 1, nil
+	}
+	for i, option := range options {
+		if strings.EqualFold(option, input) {
+			return i, nil
+		}
+	}
+	matches := fuzzyFilter(options, input)
+	switch {
+	case input == "" || len(matches) == 0:
+		return 0, fmt.Errorf("%q matches no option", input)
+	case len(matches) > 1:
+		var names []string
+		for _, i := range matches {
+			names = append(names, options[i])
+		}
+		return 0, fmt.Errorf("%q matches %s", input, strings.Join(names, ", "))
+	}
+	return matches[0], nil
+}
+
+// matchOptions resolves a comma separated answer of numbers, ranges and
+// option names to sorted option indexes
+func matchOptions(options []string, input string) ([]int, error) {
+	seen := make(map[int]bool)
+	var indexes []int
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		var found []int
+		if nums, err := parseNumberList(part, len(options)); err == nil {
+			for _, n := range nums {
+				found = append(found, n-1)
+			}
+		} else {
+			i, err := matchOption(options, part)
+			if err != nil {
+				return nil, err
+			}
+			found = []int{i}
+		}
+		for _, i := range found {
+			if !seen[i] {
+				seen[i] = true
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	sort.Ints(indexes)
+	return indexes, nil
+}
+
+// fuzzyFilter returns the indexes of options matching pattern, best first:
+// prefix matches, then substrings, then options containing the pattern's
+// characters in order
+func fuzzyFilter(options []string, pattern string) []int {
+	pattern = strings.ToLower(pattern)
+	var ranked [3][]int
+	for i, option := range options {
+		option = strings.ToLower(option)
+		switch {
+		case strings.HasPrefix(option, pattern):
+			ranked[0] = append(ranked[0], i)
+		case strings.Contains(option, pattern):
+			ranked[1] = append(ranked[1], i)
+		case isSubsequence(pattern, option):
+			ranked[2] = append(ranked[2], i)
+		}
+	}
+	return append(append(ranked[0], ranked[1]...), ranked[2]...)
+}
+
+func isSubsequence(pattern, s string) bool {
+	rest := []rune(pattern)
+	for _, r := range s {
+		if len(rest) == 0 {
+			break
+		}
+		if r == rest[0] {
+			rest = rest[1:]
+		}
+	}
+	return len(rest) == 0
+}
+
+// pick runs the terminal picker: arrow keys move, typing filters, space
+// toggles in multi mode, Enter accepts and Ctrl-C cancels
+func (p *prompter) pick(label string, options []string, checked []bool, multi bool) ([]int, error) {
+	restore, err := rawMode()
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	return p.pickKeys(label, options, checked, multi)
+}
+
+// pickKeys runs the picker on keys read from p.in, which must be a terminal
+// in raw mode or input that stands in for one
+func (p *prompter) pickKeys(label string, options []string, checked []bool, multi bool) ([]int, error) {
+	marked := make([]bool, len(options))
+	copy(marked, checked)
+	filter := ""
+	cursor := 0
+	drawn := 0
+
+	for {
+		visible := fuzzyFilter(options, filter)
+		if cursor >= len(visible) {
+			cursor = len(visible) - 1
+		}
+		if cursor < 0 {
+			cursor = 0
+		}
+		drawn = p.drawPicker(label, options, visible, marked, multi, filter, cursor, drawn)
+
+		r, _, err := p.in.ReadRune()
+		if err != nil {
+			return nil, err
+		}
+		switch r {
+		case 3: // Ctrl-C
+			p.clearPicker(drawn)
+			return nil, errCancelled
+		case '\r', '\n':
+			p.clearPicker(drawn)
+			var selected []int
+			if multi {
+				for i, m := range marked {
+					if m {
+						selected = append(selected, i)
+					}
+				}
+			} else if len(visible) > 0 {
+				selected = []int{visible[cursor]}
+			}
+			var names []string
+			for _, i := range selected {
+				names = append(names, options[i])
+			}
+			fmt.Fprintf(p.out, "%s: %s\r\n", label, strings.Join(names, ", "))
+			return selected, nil
+		case 27:
+			// Terminals send escape sequences in one write, so a lone ESC
+			// with nothing behind it is the Esc key
+			var seq []byte
+			for len(seq) < 2 && p.in.Buffered() > 0 {
+				c, _ := p.in.ReadByte()
+				seq = append(seq, c)
+			}
+			switch string(seq) {
+			case "":
+				p.clearPicker(drawn)
+				return nil, errCancelled
+			case "[A":
+				cursor--
+			case "[B":
+				cursor++
+			}
+		case 16: // Ctrl-P
+			cursor--
+		case 14: // Ctrl-N
+			cursor++
+		case 127, 8:
+			if filter != "" {
+				r := []rune(filter)
+				filter = string(r[:len(r)-1])
+				cursor = 0
+			}
+		case ' ':
+			if multi {
+				if len(visible) > 0 {
+					marked[visible[cursor]] = !marked[visible[cursor]]
+				}
+				continue
+			}
+			filter += " "
+			cursor = 0
+		default:
+			if unicode.IsPrint(r) {
+				filter += string(r)
+				cursor = 0
+			}
+		}
+	}
+}
+
+// drawPicker renders the picker over the previous drawing of drawn lines and
+// returns the number of lines drawn now
+func (p *prompter) drawPicker(label string, options []string, visible []int, marked []bool, multi bool, filter string, cursor, drawn int) int {
+	p.clearPicker(drawn)
+	hint := "↑/↓ to move, type to filter, Enter to select"
+	if multi {
+		hint = "↑/↓ to move, Space to toggle, type to filter, Enter to accept"
+	}
+	lines := []string{fmt.Sprintf("%s (%s): %s", label, hint, filter)}
+
+	start := 0
+	if cursor >= maxVisibleOptions {
+		start = cursor - maxVisibleOptions + 1
+	}
+	for n, i := range visible[start:] {
+		if n == maxVisibleOptions {
+			break
+		}
+		pointer := "  "
+		if start+n == cursor {
+			pointer = "> "
+		}
+		box := ""
+		if multi {
+			box = "[ ] "
+			if marked[i] {
+				box = "[x] "
+			}
+		}
+		lines = append(lines, pointer+box+options[i])
+	}
+	if len(visible) == 0 {
+		lines = append(lines, "  (no matches)")
+	}
+	fmt.Fprint(p.out, strings.Join(lines, "\r\n")+"\r\n")
+	return len(lines)
+}
+
+func (p *prompter) clearPicker(drawn int) {
+	if drawn > 0 {
+		fmt.Fprintf(p.out, "\x1b[%dA\r\x1b[J", drawn)
+	}
+}
+
+// rawMode switches the terminal to unbuffered input without echo and
+// returns a function restoring the previous settings
+func rawMode() (func(), error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read terminal settings: %w", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("failed to set terminal mode: %w", err)
+	}
+	return func() { stty(strings.TrimSpace(saved)) }, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// editText opens initial in the user's editor and returns the saved text
+func editText(initial string) (string, error) {
+	f, err := os.CreateTemp("", "kira-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(initial); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	f.Close()
+
+	if err := openInEditor(f.Name()); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read temporary file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
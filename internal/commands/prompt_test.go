@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyFilter(t *testing.T) {
+	options := []string{"backlog", "todo", "doing", "review", "done"}
+	assert.Equal(t, []int{1}, fuzzyFilter(options, "tod"))
+	assert.Equal(t, []int{2, 4, 1}, fuzzyFilter(options, "do"))
+	assert.Equal(t, []int{3}, fuzzyFilter(options, "rvw"))
+	assert.Empty(t, fuzzyFilter(options, "xyz"))
+}
+
+func TestMatchOption(t *testing.T) {
+	options := []string{"low", "medium", "high"}
+
+	i, err := matchOption(options, "2")
+	require.NoError(t, err)
+	assert.Equal(t, 1, i)
+
+	i, err = matchOption(options, "HIGH")
+	require.NoError(t, err)
+	assert.Equal(t, 2, i)
+
+	i, err = matchOption(options, "med")
+	require.NoError(t, err)
+	assert.Equal(t, 1, i)
+
+	_, err = matchOption(options, "4")
+	assert.Error(t, err)
+	_, err = matchOption(options, "i")
+	assert.ErrorContains(t, err, "matches medium, high")
+
+	indexes, err := matchOptions(options, "high, 1-2, low")
+	require.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2}, indexes)
+}
+
+func TestPrompterSharesInput(t *testing.T) {
+	var out bytes.Buffer
+	p := &prompter{in: bufio.NewReader(strings.NewReader("first\n3\ny\n")), out: &out}
+
+	value, err := p.line("Name: ")
+	require.NoError(t, err)
+	assert.Equal(t, "first", value)
+
+	value, err = p.selectOne("size", []string{"s", "m", "l"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, "l", value)
+
+	ok, err := p.confirm("Continue?", false)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = p.ask(func() (string, error) { return "bad", nil }, func(v string) (string, error) {
+		return "", assert.AnError
+	})
+	assert.Error(t, err, "piped answers are not retried")
+}
+
+func TestPickKeys(t *testing.T) {
+	pick := func(keys string, options ...string) ([]int, error) {
+		p := &prompter{in: bufio.NewReader(strings.NewReader(keys)), out: &bytes.Buffer{}}
+		return p.pickKeys("choice", options, nil, false)
+	}
+
+	selected, err := pick("\x1b[B\r", "one", "two")
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, selected, "arrow keys move the cursor")
+
+	selected, err = pick("né\r", "tea", "café", "naïve", "née")
+	require.NoError(t, err)
+	assert.Equal(t, []int{3}, selected, "filters on whole characters")
+
+	_, err = pick("\x1b", "one", "two")
+	assert.ErrorIs(t, err, errCancelled, "a lone Esc cancels")
+}
@@ -4,10 +4,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
 	"kira/internal/config"
+	"kira/internal/release"
 
 	"github.com/spf13/cobra"
 )
@@ -16,7 +18,8 @@ var releaseCmd = &cobra.Command{
 	Use:   "release [status|path] [subfolder]",
 	Short: "Generate release notes and archive completed work items",
 	Long: `Generates release notes and archives completed work items.
-Updates work item status to "released" before archival.`,
+Updates work item status to "released" before archival. Notes are prepended
+to the releases file and written to any outputs configured under release.outputs.`,
 	Args: cobra.MaximumNArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := checkWorkDir(); err != nil {
@@ -41,33 +44,98 @@ Updates work item status to "released" before archival.`,
 			subfolder = args[1]
 		}
 
-		return releaseWorkItems(cfg, targetPath, subfolder)
+		var opts releaseOptions
+		opts.Version, _ = cmd.Flags().GetString("version")
+		opts.Preview, _ = cmd.Flags().GetBool("preview")
+		opts.IDs, _ = cmd.Flags().GetStringSlice("ids")
+		opts.Tags, _ = cmd.Flags().GetStringSlice("tag")
+		opts.Kinds, _ = cmd.Flags().GetStringSlice("kind")
+		opts.Interactive, _ = cmd.Flags().GetBool("select")
+
+		return releaseWorkItems(cfg, targetPath, subfolder, opts)
 	},
 }
 
-func releaseWorkItems(cfg *config.Config, targetPath, subfolder string) error {
-	// Determine the source path
-	var sourcePath string
-	if strings.Contains(targetPath, "/") {
-		// Direct path provided
-		sourcePath = filepath.Join(".work", targetPath)
-	} else {
-		// Status name provided
-		statusFolder, exists := cfg.StatusFolders[targetPath]
-		if !exists {
-			return fmt.Errorf("invalid status: %s", targetPath)
+// releaseOptions controls which items a release picks up and whether it is
+// only previewed
+type releaseOptions struct {
+	Version     string
+	Preview     bool
+	IDs         []string
+	Tags        []string
+	Kinds       []string
+	Interactive bool
+}
+
+var releaseNotesCmd = &cobra.Command{
+	Use:   "notes <version> [status|path] [subfolder]",
+	Short: "Render release notes to the configured outputs without releasing",
+	Long: `Renders release notes for the work items in the given folder (done by default)
+to every output configured under release.outputs in kira.yml. Nothing is
+archived and no status is changed. Without configured outputs the notes are
+printed to stdout.`,
+	Args: cobra.RangeArgs(1, 3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
 		}
-		sourcePath = filepath.Join(".work", statusFolder)
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		targetPath := "done"
+		var subfolder string
+		if len(args) > 1 {
+			targetPath = args[1]
+		}
+		if len(args) > 2 {
+			subfolder = args[2]
+		}
+
+		return renderReleaseNotes(cfg, args[0], targetPath, subfolder)
+	},
+}
+
+func init() {
+	releaseCmd.Flags().String("version", "", "Version label for the release notes heading (e.g. v1.4.0)")
+	releaseCmd.Flags().Bool("preview", false, "Show which items would ship and the exact notes without changing anything")
+	releaseCmd.Flags().StringSlice("ids", nil, "Only release these work item IDs (comma-separated)")
+	releaseCmd.Flags().StringSlice("tag", nil, "Only release items with any of these tags")
+	releaseCmd.Flags().StringSlice("kind", nil, "Only release items of these kinds (e.g. prd,issue)")
+	releaseCmd.Flags().Bool("select", false, "Pick the items to release from an interactive checklist")
+	releaseCmd.AddCommand(releaseNotesCmd)
+}
+
+func renderReleaseNotes(cfg *config.Config, version, targetPath, subfolder string) error {
+	sourcePath, err := resolveReleaseSource(cfg, targetPath, subfolder)
+	if err != nil {
+		return err
 	}
 
-	// Add subfolder if provided
-	if subfolder != "" {
-		sourcePath = filepath.Join(sourcePath, subfolder)
+	workItems, err := getWorkItemFiles(sourcePath)
+	if err != nil {
+		return fmt.Errorf("failed to get work item files: %w", err)
 	}
 
-	// Check if source path exists
-	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-		return fmt.Errorf("source path does not exist: %s", sourcePath)
+	notes, releaseNotes, err := generateReleaseNotes(cfg, workItems, version)
+	if err != nil {
+		return fmt.Errorf("failed to generate release notes: %w", err)
+	}
+
+	if len(cfg.Release.Outputs) == 0 {
+		fmt.Println(releaseNotes)
+		return nil
+	}
+
+	return writeReleaseOutputs(cfg, notes, releaseNotes)
+}
+
+func releaseWorkItems(cfg *config.Config, targetPath, subfolder string, opts releaseOptions) error {
+	sourcePath, err := resolveReleaseSource(cfg, targetPath, subfolder)
+	if err != nil {
+		return err
 	}
 
 	// Get all work item files in the source path
@@ -76,17 +144,28 @@ func releaseWorkItems(cfg *config.Config, targetPath, subfolder string) error {
 		return fmt.Errorf("failed to get work item files: %w", err)
 	}
 
+	// Narrow down to the selected items; the rest stay where they are
+	workItems, err = selectReleaseItems(workItems, opts)
+	if err != nil {
+		return err
+	}
+
 	if len(workItems) == 0 {
 		fmt.Println("No work items found to release.")
 		return nil
 	}
 
 	// Generate release notes
-	releaseNotes, err := generateReleaseNotes(workItems)
+	notes, releaseNotes, err := generateReleaseNotes(cfg, workItems, opts.Version)
 	if err != nil {
 		return fmt.Errorf("failed to generate release notes: %w", err)
 	}
 
+	if opts.Preview {
+		printReleasePreview(cfg, sourcePath, notes, releaseNotes)
+		return nil
+	}
+
 	// Update work item statuses to "released"
 	for _, workItem := range workItems {
 		if err := updateWorkItemStatus(workItem, "released"); err != nil {
@@ -94,64 +173,294 @@ func releaseWorkItems(cfg *config.Config, targetPath, subfolder string) error {
 		}
 	}
 
+	// Update releases file
+	if err := updateReleasesFile(cfg, releaseNotes); err != nil {
+		return fmt.Errorf("failed to update releases file: %w", err)
+	}
+
+	// Write any additional configured outputs
+	if err := writeReleaseOutputs(cfg, notes, releaseNotes); err != nil {
+		return err
+	}
+
 	// Archive work items
-	archivePath, err := archiveWorkItems(workItems, sourcePath)
+	archivePath, err := archiveWorkItems(cfg, workItems, archiveBatch{
+		Type:    "release",
+		Name:    filepath.Base(sourcePath),
+		Source:  sourcePath,
+		Version: opts.Version,
+	})
 	if err != nil {
 		return fmt.Errorf("failed to archive work items: %w", err)
 	}
 
-	// Update releases file
-	if err := updateReleasesFile(cfg, releaseNotes); err != nil {
-		return fmt.Errorf("failed to update releases file: %w", err)
+	fmt.Printf("Released %d work items to %s\n", len(workItems), archivePath)
+	return nil
+}
+
+// resolveReleaseSource turns a status name or .work-relative path, plus an
+// optional subfolder, into an existing source directory
+func resolveReleaseSource(cfg *config.Config, targetPath, subfolder string) (string, error) {
+	var sourcePath string
+	if strings.Contains(targetPath, "/") {
+		// Direct path provided
+		sourcePath = filepath.Join(".work", targetPath)
+	} else {
+		// Status name provided
+		statusFolder, exists := cfg.StatusFolders[targetPath]
+		if !exists {
+			return "", fmt.Errorf("invalid status: %s", targetPath)
+		}
+		sourcePath = filepath.Join(".work", statusFolder)
+	}
+
+	// Add subfolder if provided
+	if subfolder != "" {
+		sourcePath = filepath.Join(sourcePath, subfolder)
+	}
+
+	// Check if source path exists
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		return "", fmt.Errorf("source path does not exist: %s", sourcePath)
 	}
 
-	// Remove original files
+	return sourcePath, nil
+}
+
+// selectReleaseItems filters work items by ID, tag and kind, then optionally
+// lets the user adjust the selection from a checklist
+func selectReleaseItems(workItems []string, opts releaseOptions) ([]string, error) {
+	var entries []release.Entry
 	for _, workItem := range workItems {
-		if err := os.Remove(workItem); err != nil {
-			fmt.Printf("Warning: failed to remove %s: %v\n", workItem, err)
+		entry, err := release.LoadEntry(workItem)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", workItem, err)
+		}
+		if matchesReleaseFilter(entry, opts) {
+			entries = append(entries, entry)
 		}
 	}
 
-	fmt.Printf("Released %d work items to %s\n", len(workItems), archivePath)
-	return nil
+	if opts.Interactive && len(entries) > 0 {
+		var err error
+		entries, err = promptReleaseChecklist(entries)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	selected := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		selected = append(selected, entry.Path)
+	}
+	return selected, nil
+}
+
+func matchesReleaseFilter(entry release.Entry, opts releaseOptions) bool {
+	if len(opts.IDs) > 0 && !containsString(opts.IDs, entry.ID) {
+		return false
+	}
+	if len(opts.Kinds) > 0 && !containsString(opts.Kinds, entry.Kind) {
+		return false
+	}
+	if len(opts.Tags) > 0 {
+		for _, tag := range entry.Tags {
+			if containsString(opts.Tags, tag) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
 }
 
-func generateReleaseNotes(workItems []string) (string, error) {
-	var releaseNotes []string
+// promptReleaseChecklist shows the candidates with all items checked and lets
+// the user toggle them by number until the selection is confirmed
+func promptReleaseChecklist(entries []release.Entry) ([]release.Entry, error) {
+	checked := make([]bool, len(entries))
+	for i := range checked {
+		checked[i] = true
+	}
 
-	for _, workItem := range workItems {
-		content, err := os.ReadFile(workItem)
+	if prompts.tty {
+		var labels []string
+		byLabel := make(map[string]release.Entry)
+		for _, entry := range entries {
+			label := fmt.Sprintf("%s %s (%s)", entry.ID, entry.Title, entry.Kind)
+			labels = append(labels, label)
+			byLabel[label] = entry
+		}
+		chosen, err := prompts.selectMany("items to release", labels, checked)
 		if err != nil {
-			return "", err
+			return nil, err
+		}
+		var selected []release.Entry
+		for _, label := range chosen {
+			selected = append(selected, byLabel[label])
 		}
+		return selected, nil
+	}
 
-		// Check if file has release notes section
-		if strings.Contains(string(content), "# Release Notes") {
-			// Extract release notes section
-			lines := strings.Split(string(content), "\n")
-			var inReleaseNotes bool
-			var releaseNoteLines []string
+	for {
+		fmt.Println("Items to release:")
+		for i, entry := range entries {
+			mark := " "
+			if checked[i] {
+				mark = "x"
+			}
+			fmt.Printf("%d. [%s] %s %s (%s)\n", i+1, mark, entry.ID, entry.Title, entry.Kind)
+		}
+		fmt.Print("Toggle numbers (e.g. 1,3 or 2-4), 'a' for all, 'n' for none, Enter to confirm: ")
 
-			for _, line := range lines {
-				if strings.Contains(line, "# Release Notes") {
-					inReleaseNotes = true
-					continue
-				}
-				if inReleaseNotes {
-					if strings.HasPrefix(line, "#") && !strings.Contains(line, "Release Notes") {
-						break
-					}
-					releaseNoteLines = append(releaseNoteLines, line)
+		input, err := prompts.line("")
+		if err != nil {
+			return nil, err
+		}
+
+		switch input {
+		case "":
+			var selected []release.Entry
+			for i, entry := range entries {
+				if checked[i] {
+					selected = append(selected, entry)
 				}
 			}
+			return selected, nil
+		case "a":
+			for i := range checked {
+				checked[i] = true
+			}
+		case "n":
+			for i := range checked {
+				checked[i] = false
+			}
+		default:
+			indexes, err := parseNumberList(input, len(entries))
+			if err != nil {
+				fmt.Printf("Invalid selection: %v\n", err)
+				continue
+			}
+			for _, idx := range indexes {
+				checked[idx-1] = !checked[idx-1]
+			}
+		}
+	}
+}
+
+// parseNumberList parses "1,3,5-7" into 1-based indexes no larger than max
+func parseNumberList(input string, max int) ([]int, error) {
+	var indexes []int
+	for _, part := range strings.Split(input, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi := part, part
+		if dash := strings.Index(part, "-"); dash > 0 {
+			lo, hi = part[:dash], part[dash+1:]
+		}
+		start, err := strconv.Atoi(strings.TrimSpace(lo))
+		if err != nil {
+			return nil, fmt.Errorf("not a number: %s", part)
+		}
+		end, err := strconv.Atoi(strings.TrimSpace(hi))
+		if err != nil {
+			return nil, fmt.Errorf("not a number: %s", part)
+		}
+		if start < 1 || end > max || start > end {
+			return nil, fmt.Errorf("out of range: %s", part)
+		}
+		for i := start; i <= end; i++ {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes, nil
+}
+
+func printReleasePreview(cfg *config.Config, sourcePath string, notes release.Notes, releaseNotes string) {
+	fmt.Printf("Would release %d work items from %s:\n", len(notes.Items), sourcePath)
+	for _, entry := range notes.Items {
+		fmt.Printf("  %s %s (%s)\n", entry.ID, entry.Title, entry.Kind)
+	}
+
+	fmt.Println()
+	if releaseNotes == "" {
+		fmt.Println("No release notes would be written.")
+	} else {
+		fmt.Printf("Release notes for %s:\n\n%s\n", cfg.Release.ReleasesFile, releaseNotes)
+	}
+
+	for _, out := range cfg.Release.Outputs {
+		fmt.Printf("Would write %s output to %s\n", out.Type, release.OutputPath(out.Path, notes))
+	}
+}
+
+func generateReleaseNotes(cfg *config.Config, workItems []string, version string) (release.Notes, string, error) {
+	var tmplText string
+	if cfg.Release.NotesTemplate != "" {
+		content, err := os.ReadFile(filepath.Join(".work", cfg.Release.NotesTemplate))
+		if err != nil {
+			return release.Notes{}, "", fmt.Errorf("failed to read release notes template: %w", err)
+		}
+		tmplText = string(content)
+	}
+
+	// Linked commits only add to the notes, so finding them must not block
+	// a release
+	var links commitLinks
+	if releaseUsesCommits(cfg, tmplText) {
+		var err error
+		if links, err = scanCommitLinks(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: releasing without linked commits: %v\n", err)
+			links = nil
+		}
+	}
 
-			if len(releaseNoteLines) > 0 {
-				releaseNotes = append(releaseNotes, strings.Join(releaseNoteLines, "\n"))
+	var entries []release.Entry
+	for _, workItem := range workItems {
+		entry, err := release.LoadEntry(workItem)
+		if err != nil {
+			return release.Notes{}, "", fmt.Errorf("failed to read %s: %w", workItem, err)
+		}
+		if links != nil {
+			if entry.Commits, err = links.releaseCommits(entry.ID); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: releasing %s without linked commits: %v\n", entry.ID, err)
 			}
 		}
+		entries = append(entries, entry)
 	}
 
-	return strings.Join(releaseNotes, "\n\n"), nil
+	notes := release.Build(cfg.Release, entries, version, time.Now())
+	rendered, err := release.Render(notes, cfg.Release.Format, tmplText)
+	if err != nil {
+		return release.Notes{}, "", err
+	}
+	return notes, rendered, nil
+}
+
+// releaseUsesCommits reports whether the notes template or an output shows
+// linked commits, which are costly to find in a long history
+func releaseUsesCommits(cfg *config.Config, tmplText string) bool {
+	if strings.Contains(tmplText, ".Commits") {
+		return true
+	}
+	for _, out := range cfg.Release.Outputs {
+		if out.Type == release.OutputJSON {
+			return true
+		}
+	}
+	return false
+}
+
+func writeReleaseOutputs(cfg *config.Config, notes release.Notes, releaseNotes string) error {
+	written, err := release.WriteOutputs(cfg.Release.Outputs, notes, releaseNotes)
+	for _, path := range written {
+		fmt.Printf("Wrote %s\n", path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write release outputs: %w", err)
+	}
+	return nil
 }
 
 func updateReleasesFile(cfg *config.Config, releaseNotes string) error {
@@ -172,8 +481,7 @@ func updateReleasesFile(cfg *config.Config, releaseNotes string) error {
 	}
 
 	// Prepend new release notes
-	date := time.Now().Format("2006-01-02")
-	newContent := fmt.Sprintf("# Release %s\n\n%s\n\n%s", date, releaseNotes, content)
+	newContent := release.Prepend(content, releaseNotes, cfg.Release.Format)
 
 	// Write back to file
 	if err := os.WriteFile(releasesPath, []byte(newContent), 0644); err != nil {
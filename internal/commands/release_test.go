@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func writeDoneItem(t *testing.T, id, kind, tags string) string {
+	t.Helper()
+	path := ".work/4_done/" + id + "-item." + kind + ".md"
+	content := `---
+id: ` + id + `
+title: Item ` + id + `
+status: done
+kind: ` + kind + `
+tags: ` + tags + `
+created: 2024-01-01
+---
+
+# Item ` + id + `
+
+## Release Notes
+Notes for ` + id + `
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestSelectReleaseItems(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+
+	os.MkdirAll(".work/4_done", 0755)
+	first := writeDoneItem(t, "001", "prd", "[ui]")
+	second := writeDoneItem(t, "002", "issue", "[api, security]")
+	third := writeDoneItem(t, "003", "prd", "[api]")
+	all := []string{first, second, third}
+
+	t.Run("selects by ids", func(t *testing.T) {
+		selected, err := selectReleaseItems(all, releaseOptions{IDs: []string{"001", "003"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{first, third}, selected)
+	})
+
+	t.Run("selects by tag and kind together", func(t *testing.T) {
+		selected, err := selectReleaseItems(all, releaseOptions{Tags: []string{"api"}, Kinds: []string{"prd"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{third}, selected)
+	})
+
+	t.Run("preview leaves items untouched", func(t *testing.T) {
+		cfg := &config.DefaultConfig
+		err := releaseWorkItems(cfg, "done", "", releaseOptions{Preview: true})
+		require.NoError(t, err)
+		for _, path := range all {
+			assert.FileExists(t, path)
+		}
+		assert.NoFileExists(t, cfg.Release.ReleasesFile)
+	})
+
+	t.Run("unselected items stay in done", func(t *testing.T) {
+		cfg := &config.DefaultConfig
+		err := releaseWorkItems(cfg, "done", "", releaseOptions{Kinds: []string{"issue"}})
+		require.NoError(t, err)
+		assert.FileExists(t, first)
+		assert.NoFileExists(t, second)
+		assert.FileExists(t, third)
+
+		content, err := os.ReadFile(first)
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "status: done")
+	})
+}
+
+func TestParseNumberList(t *testing.T) {
+	indexes, err := parseNumberList("1, 3-4", 4)
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 4}, indexes)
+
+	_, err = parseNumberList("5", 4)
+	assert.Error(t, err)
+}
@@ -22,13 +22,25 @@ func Execute() error {
 func init() {
 	rootCmd.AddCommand(initCmd)
 	rootCmd.AddCommand(newCmd)
+	rootCmd.AddCommand(importCmd)
 	rootCmd.AddCommand(moveCmd)
+	rootCmd.AddCommand(showCmd)
+	rootCmd.AddCommand(branchCmd)
+	rootCmd.AddCommand(boardCmd)
 	rootCmd.AddCommand(ideaCmd)
 	rootCmd.AddCommand(lintCmd)
 	rootCmd.AddCommand(doctorCmd)
 	rootCmd.AddCommand(releaseCmd)
 	rootCmd.AddCommand(abandonCmd)
+	rootCmd.AddCommand(archiveCmd)
+	rootCmd.AddCommand(templateCmd)
 	rootCmd.AddCommand(saveCmd)
+	rootCmd.AddCommand(logCmd)
+	rootCmd.AddCommand(gitCmd)
+	rootCmd.AddCommand(mergeDriverCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(mcpCmd)
+	rootCmd.AddCommand(watchCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
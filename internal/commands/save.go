@@ -2,8 +2,8 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
 	"time"
@@ -17,7 +17,10 @@ var saveCmd = &cobra.Command{
 	Use:   "save [commit-message]",
 	Short: "Update work items and commit changes to git",
 	Long: `Updates the updated field in work items and commits changes to git.
-Validates all non-archived work items before staging.`,
+Validates all non-archived work items before staging.
+
+Only kira's own files are committed: .work/, kira.yml and the releases file.
+Other staged changes are left staged and are not part of the commit.`,
 	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if err := checkWorkDir(); err != nil {
@@ -34,11 +37,30 @@ Validates all non-archived work items before staging.`,
 			commitMessage = args[0]
 		}
 
-		return saveWorkItems(cfg, commitMessage)
+		push, _ := cmd.Flags().GetBool("push")
+		amend, _ := cmd.Flags().GetBool("amend")
+		sign, _ := cmd.Flags().GetBool("sign")
+
+		return saveWorkItems(cfg, os.Stdout, commitMessage, saveOptions{Push: push, Amend: amend, Sign: sign})
 	},
 }
 
-func saveWorkItems(cfg *config.Config, commitMessage string) error {
+func init() {
+	saveCmd.Flags().Bool("push", false, "Push the commit to the upstream branch")
+	saveCmd.Flags().Bool("amend", false, "Amend the previous commit instead of creating a new one")
+	saveCmd.Flags().BoolP("sign", "S", false, "GPG-sign the commit")
+}
+
+// saveOptions are the git options of kira save
+type saveOptions struct {
+	Push  bool
+	Amend bool
+	Sign  bool
+}
+
+// saveWorkItems validates and commits the work items, reporting progress on
+// out
+func saveWorkItems(cfg *config.Config, out io.Writer, commitMessage string, opts saveOptions) error {
 	// Validate all work items first
 	result, err := validation.ValidateWorkItems(cfg)
 	if err != nil {
@@ -46,77 +68,140 @@ func saveWorkItems(cfg *config.Config, commitMessage string) error {
 	}
 
 	if result.HasErrors() {
-		fmt.Println("Validation errors found:")
+		fmt.Fprintln(out, "Validation errors found:")
 		for _, err := range result.Errors {
-			fmt.Printf("  %s\n", err.Error())
+			fmt.Fprintf(out, "  %s\n", err.Error())
 		}
 		return fmt.Errorf("validation failed - fix errors before saving")
 	}
 
+	if !isGitRepo() {
+		return fmt.Errorf("not a git repository: kira save commits work items with git")
+	}
+
 	// Update timestamps for modified work items
 	if err := updateWorkItemTimestamps(); err != nil {
 		return fmt.Errorf("failed to update timestamps: %w", err)
 	}
 
-	// Check for external changes
-	hasExternalChanges, err := checkExternalChanges()
-	if err != nil {
-		return fmt.Errorf("failed to check for external changes: %w", err)
+	paths := kiraPaths(cfg)
+	if _, err := runGit(append([]string{"add", "-A", "--"}, paths...)...); err != nil {
+		return fmt.Errorf("failed to stage work changes: %w", err)
 	}
-
-	if hasExternalChanges {
-		fmt.Println("Warning: External changes detected outside .work/ directory.")
-		fmt.Println("Skipping commit to avoid mixing work item changes with other changes.")
-		return nil
+	if _, err := runGit(append([]string{"diff", "--cached", "--quiet", "--"}, paths...)...); err == nil && !opts.Amend {
+		fmt.Fprintln(out, "Nothing to save.")
+		return pushChanges(out, opts)
 	}
 
-	// Stage only .work/ directory changes
-	if err := stageWorkChanges(); err != nil {
-		return fmt.Errorf("failed to stage work changes: %w", err)
+	// Describe the changes in the message unless one was given
+	summary, err := describeChanges(cfg, commitBase(opts.Amend))
+	if err != nil {
+		return fmt.Errorf("failed to describe changes: %w", err)
 	}
-
-	// Commit changes
-	if commitMessage == "" {
-		commitMessage = cfg.Commit.DefaultMessage
+	commitMessage, err = buildCommitMessage(cfg, commitMessage, summary)
+	if err != nil {
+		return err
 	}
 
-	if err := commitChanges(commitMessage); err != nil {
+	if err := commitChanges(commitMessage, paths, opts); err != nil {
 		return fmt.Errorf("failed to commit changes: %w", err)
 	}
 
-	fmt.Println("Work items saved and committed successfully.")
+	if others := stagedOutside(); others > 0 {
+		fmt.Fprintf(out, "Left %d staged change(s) outside kira's files out of the commit.\n", others)
+	}
+
+	fmt.Fprintln(out, "Work items saved and committed successfully.")
+	return pushChanges(out, opts)
+}
+
+func pushChanges(out io.Writer, opts saveOptions) error {
+	if !opts.Push {
+		return nil
+	}
+	if _, err := runGit("push"); err != nil {
+		return fmt.Errorf("failed to push: %w", err)
+	}
+	fmt.Fprintln(out, "Pushed to upstream.")
 	return nil
 }
 
-func updateWorkItemTimestamps() error {
-	currentTime := time.Now().Format("2006-01-02T15:04:05Z")
-	
-	return filepath.Walk(".work", func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
+// kiraPaths is the pathspec kira save stages and commits: the work folder,
+// the config file and the releases file, when they exist or are tracked
+func kiraPaths(cfg *config.Config) []string {
+	paths := []string{".work"}
+	for _, path := range []string{config.ConfigPath(), cfg.Release.ReleasesFile} {
+		if path == "" || strings.HasPrefix(filepath.ToSlash(path), ".work/") || containsString(paths, path) {
+			continue
 		}
-		
-		if info.IsDir() {
-			return nil
+		if _, err := os.Stat(path); err == nil || isTrackedByGit(path) {
+			paths = append(paths, path)
 		}
-		
+	}
+	return paths
+}
+
+// updatedTimeFormat is the layout of the updated: timestamp
+const updatedTimeFormat = "2006-01-02T15:04:05Z"
+
+// updateWorkItemTimestamps stamps updated: on the work items git reports as
+// added or modified since the last commit, leaving unchanged items alone
+func updateWorkItemTimestamps() error {
+	currentTime := time.Now().UTC().Format(updatedTimeFormat)
+
+	files, err := changedWorkFiles()
+	if err != nil {
+		return err
+	}
+	for _, path := range files {
 		// Skip template files, IDEAS.md, and archived items
-		if strings.Contains(path, "template") || 
-		   strings.HasSuffix(path, "IDEAS.md") || 
-		   strings.Contains(path, "z_archive") {
-			return nil
+		if strings.Contains(path, "template") ||
+			strings.HasSuffix(path, "IDEAS.md") ||
+			strings.Contains(path, "z_archive") {
+			continue
 		}
-		
+
 		// Only process markdown files
 		if !strings.HasSuffix(path, ".md") {
-			return nil
+			continue
 		}
-		
-		// Update the updated timestamp
-		return updateFileTimestamp(path, currentTime)
-	})
+
+		if err := updateFileTimestamp(path, currentTime); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
+// changedWorkFiles lists the files under .work that are new, or differ from
+// the last commit in the index or the working tree
+func changedWorkFiles() ([]string, error) {
+	unstaged, err := runGit("ls-files", "-z", "--modified", "--others", "--exclude-standard", "--", ".work")
+	if err != nil {
+		return nil, err
+	}
+	staged, err := runGit("diff", "--cached", "--name-only", "--relative", "-z", "--diff-filter=d", commitBase(false), "--", ".work")
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	seen := make(map[string]bool)
+	for _, path := range strings.Split(unstaged+staged, "\x00") {
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		if _, err := os.Stat(path); err != nil {
+			continue // deleted
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// updateFileTimestamp sets updated: in a work item's front matter, adding it
+// after created: or at the end of the front matter
 func updateFileTimestamp(filePath, timestamp string) error {
 	content, err := os.ReadFile(filePath)
 	if err != nil {
@@ -124,68 +209,56 @@ func updateFileTimestamp(filePath, timestamp string) error {
 	}
 
 	lines := strings.Split(string(content), "\n")
-	updated := false
-
-	for i, line := range lines {
-		if strings.HasPrefix(strings.TrimSpace(line), "updated:") {
-			lines[i] = fmt.Sprintf("updated: %s", timestamp)
-			updated = true
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return nil // not a work item
+	}
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			end = i
 			break
 		}
 	}
+	if end < 0 {
+		return nil
+	}
 
-	// If no updated field found, add it after the created field
-	if !updated {
-		for i, line := range lines {
-			if strings.HasPrefix(strings.TrimSpace(line), "created:") {
-				// Insert updated field after created field
-				newLines := make([]string, len(lines)+1)
-				copy(newLines, lines[:i+1])
-				newLines[i+1] = fmt.Sprintf("updated: %s", timestamp)
-				copy(newLines[i+2:], lines[i+1:])
-				lines = newLines
-				break
-			}
+	stamp := fmt.Sprintf("updated: %s", timestamp)
+	insertAt := end
+	for i := 1; i < end; i++ {
+		if strings.HasPrefix(lines[i], "updated:") {
+			lines[i] = stamp
+			return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644)
+		}
+		if strings.HasPrefix(lines[i], "created:") {
+			insertAt = i + 1
 		}
 	}
 
+	lines = append(lines[:insertAt], append([]string{stamp}, lines[insertAt:]...)...)
 	return os.WriteFile(filePath, []byte(strings.Join(lines, "\n")), 0644)
 }
 
-func checkExternalChanges() (bool, error) {
-	// Check git status for changes outside .work/
-	cmd := exec.Command("git", "status", "--porcelain")
-	output, err := cmd.Output()
-	if err != nil {
-		// If git is not available or not a git repo, assume no external changes
-		return false, nil
-	}
-
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		if line != "" && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "??") {
-			// Check if the change is outside .work/
-			parts := strings.Fields(line)
-			if len(parts) > 1 {
-				filePath := parts[1]
-				if !strings.HasPrefix(filePath, ".work/") {
-					return true, nil
-				}
-			}
-		}
+// commitChanges commits the given paths only, so changes staged elsewhere
+// are neither committed nor a reason to refuse
+func commitChanges(message string, paths []string, opts saveOptions) error {
+	args := []string{"commit", "-m", message}
+	if opts.Amend {
+		args = append(args, "--amend")
 	}
-
-	return false, nil
-}
-
-func stageWorkChanges() error {
-	// Stage all changes in .work/ directory
-	cmd := exec.Command("git", "add", ".work/")
-	return cmd.Run()
+	if opts.Sign {
+		args = append(args, "-S")
+	}
+	args = append(args, "--")
+	_, err := runGit(append(args, paths...)...)
+	return err
 }
 
-func commitChanges(message string) error {
-	cmd := exec.Command("git", "commit", "-m", message)
-	return cmd.Run()
+// stagedOutside counts the changes still staged after the commit
+func stagedOutside() int {
+	out, err := runGit("diff", "--cached", "--name-only", "-z")
+	if err != nil {
+		return 0
+	}
+	return len(strings.FieldsFunc(out, func(r rune) bool { return r == 0 }))
 }
-
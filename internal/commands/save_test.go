@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+// initGitWorkspace creates a kira workspace in a fresh git repository with
+// everything committed
+func initGitWorkspace(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.Chdir(tmpDir))
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"config", "user.email", "test@example.com"},
+		{"config", "user.name", "Test User"},
+	} {
+		_, err := runGit(args...)
+		require.NoError(t, err)
+	}
+	require.NoError(t, initializeWorkspace("."))
+	_, err := runGit("add", "-A")
+	require.NoError(t, err)
+	_, err = runGit("commit", "-q", "-m", "init")
+	require.NoError(t, err)
+}
+
+func TestSaveCommitsOnlyKiraPaths(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	item := "---\nid: 001\ntitle: Save Test\nstatus: todo\nkind: task\ncreated: 2024-01-01\n---\n"
+	require.NoError(t, os.WriteFile(".work/1_todo/001-save-test.task.md", []byte(item), 0644))
+	require.NoError(t, os.WriteFile("RELEASES.md", []byte("# Releases\n"), 0644))
+	require.NoError(t, os.WriteFile("notes with spaces.txt", []byte("unrelated\n"), 0644))
+	_, err = runGit("add", "notes with spaces.txt")
+	require.NoError(t, err)
+
+	require.NoError(t, saveWorkItems(cfg, os.Stdout, "Save test", saveOptions{}))
+
+	files, err := runGit("show", "--name-only", "--pretty=", "HEAD")
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{".work/1_todo/001-save-test.task.md", "RELEASES.md"}, strings.Fields(files))
+
+	status, err := runGit("status", "--porcelain")
+	require.NoError(t, err)
+	assert.Equal(t, "A  \"notes with spaces.txt\"\n", status)
+
+	require.NoError(t, saveWorkItems(cfg, os.Stdout, "Amended", saveOptions{Amend: true}))
+	log, err := runGit("log", "--pretty=%s")
+	require.NoError(t, err)
+	assert.Equal(t, "Amended\ninit\n", log)
+}
+
+func TestSaveGeneratesCommitMessage(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	item := "---\nid: 012\ntitle: Login fails on Safari\nstatus: todo\nkind: issue\ncreated: 2024-01-01\n---\n"
+	require.NoError(t, os.WriteFile(".work/1_todo/012-login-fails-on-safari.issue.md", []byte(item), 0644))
+	require.NoError(t, saveWorkItems(cfg, os.Stdout, "", saveOptions{}))
+
+	log, err := runGit("log", "-1", "--pretty=%B")
+	require.NoError(t, err)
+	assert.Equal(t, "chore(kira): create 012 (issue: Login fails on Safari)\n\nKira-Items: 012\n\n", log)
+
+	require.NoError(t, os.MkdirAll(".work/2_doing", 0755))
+	_, err = runGit("mv", ".work/1_todo/012-login-fails-on-safari.issue.md", ".work/2_doing/012-login-fails-on-safari.issue.md")
+	require.NoError(t, err)
+	require.NoError(t, updateWorkItemStatus(".work/2_doing/012-login-fails-on-safari.issue.md", "doing"))
+	require.NoError(t, saveWorkItems(cfg, os.Stdout, "", saveOptions{}))
+
+	log, err = runGit("log", "-1", "--pretty=%s%n%(trailers:key=Kira-Items,valueonly)")
+	require.NoError(t, err)
+	assert.Equal(t, "chore(kira): move 012 todo→doing\n012\n\n", log)
+}
+
+func TestBuildCommitMessage(t *testing.T) {
+	cfg := config.DefaultConfig
+	summary := commitSummary{
+		Type:    "chore",
+		Scope:   "kira",
+		Summary: "create 001 (task: A); create 002 (task: B); create 003 (task: C); and 2 more",
+		Events:  []string{"create 001 (task: A)", "create 002 (task: B)", "create 003 (task: C)", "update 004", "update 005"},
+		Items:   []string{"001", "002", "003", "004", "005"},
+	}
+
+	message, err := buildCommitMessage(&cfg, "", summary)
+	require.NoError(t, err)
+	assert.Equal(t, "chore(kira): create 001 (task: A); create 002 (task: B); create 003 (task: C); and 2 more\n\n"+
+		"- create 001 (task: A)\n- create 002 (task: B)\n- create 003 (task: C)\n- update 004\n- update 005\n\n"+
+		"Kira-Items: 001,002,003,004,005", message)
+
+	message, err = buildCommitMessage(&cfg, "Custom", summary)
+	require.NoError(t, err)
+	assert.Equal(t, "Custom\n\nKira-Items: 001,002,003,004,005", message)
+
+	cfg.Commit.MessageTemplate = "kira: {{ .Summary }}"
+	message, err = buildCommitMessage(&cfg, "", commitSummary{Summary: "update 004", Events: []string{"update 004"}})
+	require.NoError(t, err)
+	assert.Equal(t, "kira: update 004", message)
+
+	message, err = buildCommitMessage(&cfg, "", commitSummary{})
+	require.NoError(t, err)
+	assert.Equal(t, "Update work items", message)
+}
+
+func TestSaveStampsOnlyChangedItems(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+
+	untouched := "---\nid: 001\ntitle: Untouched\nstatus: todo\nkind: task\ncreated: 2024-01-01\n---\n"
+	require.NoError(t, os.WriteFile(".work/1_todo/001-untouched.task.md", []byte(untouched), 0644))
+	_, err := runGit("add", "-A")
+	require.NoError(t, err)
+	_, err = runGit("commit", "-q", "-m", "add item")
+	require.NoError(t, err)
+
+	// No created line: updated goes at the end of the front matter
+	changed := "---\nid: 002\ntitle: Changed\nstatus: todo\nkind: task\n---\nBody\n"
+	require.NoError(t, os.WriteFile(".work/1_todo/002-changed.task.md", []byte(changed), 0644))
+
+	// A local zone far from UTC shows whether the stamp is really UTC
+	local := time.Local
+	time.Local = time.FixedZone("UTC+10", 10*3600)
+	defer func() { time.Local = local }()
+
+	before := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, updateWorkItemTimestamps())
+
+	content, err := os.ReadFile(".work/1_todo/001-untouched.task.md")
+	require.NoError(t, err)
+	assert.Equal(t, untouched, string(content))
+
+	content, err = os.ReadFile(".work/1_todo/002-changed.task.md")
+	require.NoError(t, err)
+	lines := strings.Split(string(content), "\n")
+	require.Len(t, lines, 9)
+	assert.Equal(t, "---", lines[6])
+	stamp, err := time.Parse("2006-01-02T15:04:05Z", strings.TrimPrefix(lines[5], "updated: "))
+	require.NoError(t, err)
+	assert.False(t, stamp.Before(before), "updated should be the current UTC time")
+	assert.WithinDuration(t, time.Now().UTC(), stamp, time.Minute)
+}
@@ -0,0 +1,462 @@
+package commands
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"kira/internal/config"
+)
+
+//go:embed web/index.html
+var webUI []byte
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a web UI and JSON API for the workspace",
+	Long: `Serves the workspace over HTTP: a web UI with a board, item view and
+markdown editor at /, and a JSON API under /api. Changes are written to disk
+as the CLI would make them; with --auto-save each change is also committed
+like 'kira save'. Browsers are told about changes to .work, from the UI or
+anywhere else, over server-sent events at /api/events.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		addr, _ := cmd.Flags().GetString("addr")
+		autoSave, _ := cmd.Flags().GetBool("auto-save")
+		if autoSave && !isGitRepo() {
+			return fmt.Errorf("--auto-save needs a git repository")
+		}
+
+		s := newServer(cfg, addr, autoSave)
+		stop := make(chan struct{})
+		defer close(stop)
+		go pollWorkChanges(time.Second, stop, s.events.publish)
+
+		fmt.Printf("Serving kira on http://%s (Ctrl-C to stop)\n", displayAddr(addr))
+		return http.ListenAndServe(addr, s.routes())
+	},
+}
+
+func init() {
+	serveCmd.Flags().String("addr", "localhost:8080", "Address to listen on, e.g. :8080 for every interface")
+	serveCmd.Flags().Bool("auto-save", false, "Commit every change made through the server")
+}
+
+func displayAddr(addr string) string {
+	if strings.HasPrefix(addr, ":") {
+		return "localhost" + addr
+	}
+	return addr
+}
+
+// server serves the API. Requests are handled one at a time so writes
+// never interleave.
+type server struct {
+	cfg      *config.Config
+	addr     string // listen address, which Host headers must match
+	autoSave bool
+	mu       sync.Mutex
+	events   *eventHub
+}
+
+func newServer(cfg *config.Config, addr string, autoSave bool) *server {
+	return &server{cfg: cfg, addr: addr, autoSave: autoSave, events: newEventHub()}
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.handleUI)
+	mux.HandleFunc("/api/items", s.locked(s.handleItems))
+	mux.HandleFunc("/api/items/", s.locked(s.handleItem))
+	mux.HandleFunc("/api/board", s.locked(s.handleBoard))
+	mux.HandleFunc("/api/search", s.locked(s.handleSearch))
+	mux.HandleFunc("/api/lint", s.locked(s.handleLint))
+	mux.HandleFunc("/api/events", s.events.serve)
+	return s.guard(mux)
+}
+
+// guard rejects requests for another host name, which DNS rebinding would
+// send, and writes from pages on other origins
+func (s *server) guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reject := func(format string, args ...interface{}) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"error": fmt.Sprintf(format, args...)})
+		}
+		if !s.allowedHost(r.Host) {
+			reject("host %q does not match the server address", r.Host)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			if origin := r.Header.Get("Origin"); origin != "" && origin != "http://"+r.Host {
+				reject("requests from %s are not allowed", origin)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether a Host header names the server: its port on
+// the listen host or loopback, or, when listening on every interface, on
+// any IP address or this machine's name
+func (s *server) allowedHost(hostport string) bool {
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		return false
+	}
+	listenHost, listenPort, err := net.SplitHostPort(s.addr)
+	if err != nil || port != listenPort {
+		return false
+	}
+	host = strings.ToLower(strings.Trim(host, "[]"))
+	if host == "localhost" || host == strings.ToLower(listenHost) {
+		return true
+	}
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+	if listenIP := net.ParseIP(listenHost); listenHost == "" || listenIP != nil && listenIP.IsUnspecified() {
+		name, _ := os.Hostname()
+		return ip != nil || host == strings.ToLower(name)
+	}
+	return false
+}
+
+// apiError is an error with the HTTP status it is reported with
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e apiError) Error() string { return e.err.Error() }
+
+func badRequest(err error) error {
+	return apiError{status: http.StatusBadRequest, err: err}
+}
+
+// handlerFunc is an API handler returning its response value
+type handlerFunc func(r *http.Request) (int, interface{}, error)
+
+// locked serialises a handler and writes its result or error as JSON.
+// Unknown work items are 404s and other errors 400s, unless they say
+// otherwise.
+func (s *server) locked(h handlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		status, v, err := h(r)
+		s.mu.Unlock()
+
+		if err != nil {
+			status = http.StatusBadRequest
+			var apiErr apiError
+			switch {
+			case errors.As(err, &apiErr):
+				status = apiErr.status
+			case errors.Is(err, errWorkItemNotFound):
+				status = http.StatusNotFound
+			}
+			v = map[string]string{"error": err.Error()}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(v)
+	}
+}
+
+func methodNotAllowed(r *http.Request) error {
+	return apiError{status: http.StatusMethodNotAllowed, err: fmt.Errorf("%s is not allowed on %s", r.Method, r.URL.Path)}
+}
+
+// decodeBody decodes a JSON request body. Requiring the JSON content type
+// means browsers will not send it cross-origin without asking first.
+func decodeBody(r *http.Request, v interface{}) error {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+		return apiError{status: http.StatusUnsupportedMediaType, err: fmt.Errorf("Content-Type must be application/json")}
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return badRequest(fmt.Errorf("invalid JSON body: %w", err))
+	}
+	return nil
+}
+
+func requestFilter(r *http.Request) itemFilter {
+	q := r.URL.Query()
+	return itemFilter{Assigned: q.Get("assigned"), Tag: q.Get("tag"), Kind: q.Get("kind")}
+}
+
+func (s *server) handleUI(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(webUI)
+}
+
+// handleItems lists work items (GET) or creates one from an item spec (POST)
+func (s *server) handleItems(r *http.Request) (int, interface{}, error) {
+	switch r.Method {
+	case http.MethodGet:
+		items, err := listItems(s.cfg, requestFilter(r))
+		if err != nil {
+			return 0, nil, err
+		}
+		if status := r.URL.Query().Get("status"); status != "" {
+			var filtered []itemSummary
+			for _, item := range items {
+				if item.Status == status {
+					filtered = append(filtered, item)
+				}
+			}
+			items = filtered
+		}
+		if items == nil {
+			items = []itemSummary{}
+		}
+		return http.StatusOK, items, nil
+	case http.MethodPost:
+		var spec itemSpec
+		if err := decodeBody(r, &spec); err != nil {
+			return 0, nil, err
+		}
+		planned, err := planItems(s.cfg, []itemSpec{spec})
+		if err != nil {
+			return 0, nil, err
+		}
+		if err := writeItems(planned); err != nil {
+			return 0, nil, err
+		}
+		s.saved()
+		item, err := loadItemDetail(s.cfg, planned[0].ID)
+		return http.StatusCreated, item, err
+	}
+	return 0, nil, methodNotAllowed(r)
+}
+
+// handleItem serves /api/items/{id}, /api/items/{id}/move and
+// /api/items/{id}/comments
+func (s *server) handleItem(r *http.Request) (int, interface{}, error) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/items/"), "/"), "/")
+	id := parts[0]
+	action := ""
+	if len(parts) > 1 {
+		action = strings.Join(parts[1:], "/")
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+	case action == "" && r.Method == http.MethodPatch:
+		var patch struct {
+			Fields  map[string]interface{} `json:"fields"`
+			Content *string                `json:"content"`
+		}
+		if err := decodeBody(r, &patch); err != nil {
+			return 0, nil, err
+		}
+		if patch.Content != nil {
+			if err := setItemContent(s.cfg, os.Stdout, id, *patch.Content); err != nil {
+				return 0, nil, err
+			}
+		}
+		if len(patch.Fields) > 0 {
+			if err := updateItemFields(s.cfg, os.Stdout, id, patch.Fields); err != nil {
+				return 0, nil, err
+			}
+		}
+		s.saved()
+	case action == "move" && r.Method == http.MethodPost:
+		var move struct {
+			Status string `json:"status"`
+		}
+		if err := decodeBody(r, &move); err != nil {
+			return 0, nil, err
+		}
+		if err := moveItem(s.cfg, os.Stdout, id, move.Status); err != nil {
+			return 0, nil, err
+		}
+		s.saved()
+	case action == "comments" && r.Method == http.MethodPost:
+		var comment struct {
+			Text   string `json:"text"`
+			Author string `json:"author"`
+		}
+		if err := decodeBody(r, &comment); err != nil {
+			return 0, nil, err
+		}
+		if err := addItemComment(s.cfg, id, comment.Author, comment.Text); err != nil {
+			return 0, nil, err
+		}
+		s.saved()
+	case action == "" || action == "move" || action == "comments":
+		return 0, nil, methodNotAllowed(r)
+	default:
+		return 0, nil, apiError{status: http.StatusNotFound, err: fmt.Errorf("unknown path %s", r.URL.Path)}
+	}
+
+	item, err := loadItemDetail(s.cfg, id)
+	return http.StatusOK, item, err
+}
+
+func (s *server) handleBoard(r *http.Request) (int, interface{}, error) {
+	if r.Method != http.MethodGet {
+		return 0, nil, methodNotAllowed(r)
+	}
+	columns, err := buildBoard(s.cfg, requestFilter(r))
+	return http.StatusOK, columns, err
+}
+
+func (s *server) handleSearch(r *http.Request) (int, interface{}, error) {
+	if r.Method != http.MethodGet {
+		return 0, nil, methodNotAllowed(r)
+	}
+	hits, err := searchItems(s.cfg, r.URL.Query().Get("q"))
+	return http.StatusOK, hits, err
+}
+
+func (s *server) handleLint(r *http.Request) (int, interface{}, error) {
+	if r.Method != http.MethodGet {
+		return 0, nil, methodNotAllowed(r)
+	}
+	issues, err := lintIssues(s.cfg)
+	return http.StatusOK, issues, err
+}
+
+// saved commits a change when auto-save is on. A failed save is logged and
+// leaves the change on disk for the next save.
+func (s *server) saved() {
+	if !s.autoSave {
+		return
+	}
+	if err := saveWorkItems(s.cfg, os.Stdout, "", saveOptions{}); err != nil {
+		log.Printf("auto-save failed: %v", err)
+	}
+}
+
+// eventHub fans change notifications out to the connected browsers
+type eventHub struct {
+	mu   sync.Mutex
+	subs map[chan []string]bool
+}
+
+func newEventHub() *eventHub {
+	return &eventHub{subs: make(map[chan []string]bool)}
+}
+
+func (h *eventHub) publish(paths []string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs {
+		select {
+		case ch <- paths:
+		default: // a slow browser misses an event, not the next one
+		}
+	}
+}
+
+// serve streams a "change" event with the changed paths to one browser
+func (h *eventHub) serve(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	ch := make(chan []string, 8)
+	h.mu.Lock()
+	h.subs[ch] = true
+	h.mu.Unlock()
+	defer func() {
+		h.mu.Lock()
+		delete(h.subs, ch)
+		h.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	fmt.Fprint(w, ": connected\n\n")
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(30 * time.Second)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case paths := <-ch:
+			data, _ := json.Marshal(map[string][]string{"paths": paths})
+			fmt.Fprintf(w, "event: change\ndata: %s\n\n", data)
+		}
+		flusher.Flush()
+	}
+}
+
+// pollWorkChanges scans .work every interval and calls changed with the
+// files added, modified or removed since the last scan
+func pollWorkChanges(interval time.Duration, stop <-chan struct{}, changed func([]string)) {
+	before := snapshotWork()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		after := snapshotWork()
+		if paths := changedPaths(before, after); len(paths) > 0 {
+			changed(paths)
+		}
+		before = after
+	}
+}
+
+// snapshotWork records the size and modification time of every file in .work
+func snapshotWork() map[string]string {
+	files := make(map[string]string)
+	filepath.Walk(".work", func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			files[filepath.ToSlash(path)] = fmt.Sprintf("%d:%d", info.Size(), info.ModTime().UnixNano())
+		}
+		return nil
+	})
+	return files
+}
+
+func changedPaths(before, after map[string]string) []string {
+	var paths []string
+	for path, stamp := range after {
+		if before[path] != stamp {
+			paths = append(paths, path)
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
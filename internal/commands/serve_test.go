@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestServeAPI(t *testing.T) {
+	initGitWorkspace(t)
+	defer os.Chdir("/")
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	handler := newServer(cfg, "localhost:8080", true).routes()
+
+	call := func(method, path, body string, out interface{}) int {
+		t.Helper()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Host = "localhost:8080"
+		if body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if out != nil {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), out), rec.Body.String())
+		}
+		return rec.Code
+	}
+
+	var created itemDetail
+	assert.Equal(t, http.StatusCreated, call("POST", "/api/items", `{"kind":"task","title":"Login page","status":"todo"}`, &created))
+	assert.Equal(t, "001", created.ID)
+	assert.Equal(t, "todo", created.Status)
+	assert.Equal(t, "001", created.Fields["id"], "IDs keep their leading zeros")
+
+	var items []itemSummary
+	assert.Equal(t, http.StatusOK, call("GET", "/api/items?status=todo", "", &items))
+	require.Len(t, items, 1)
+	assert.Equal(t, "Login page", items[0].Title)
+
+	var item itemDetail
+	assert.Equal(t, http.StatusOK, call("PATCH", "/api/items/001", `{"fields":{"assigned":"alice","tags":["ui","auth"]}}`, &item))
+	assert.Equal(t, "alice", item.Assigned)
+	assert.Equal(t, []interface{}{"ui", "auth"}, item.Fields["tags"])
+	assert.Contains(t, item.Content, "tags: [ui, auth]\n")
+
+	assert.Equal(t, http.StatusOK, call("POST", "/api/items/001/move", `{"status":"doing"}`, &item))
+	assert.Equal(t, "doing", item.Status)
+	assert.FileExists(t, ".work/2_doing/001-login-page.task.md")
+
+	assert.Equal(t, http.StatusOK, call("POST", "/api/items/001/comments", `{"text":"Needs a design","author":"bob"}`, &item))
+	assert.Regexp(t, `## Comments\n\n- \[[0-9: -]+\] Needs a design \(by bob\)\n$`, item.Content)
+
+	var hits []searchHit
+	assert.Equal(t, http.StatusOK, call("GET", "/api/search?q=DESIGN", "", &hits))
+	require.Len(t, hits, 1)
+	assert.Equal(t, "001", hits[0].ID)
+
+	var issues []lintIssue
+	assert.Equal(t, http.StatusOK, call("GET", "/api/lint", "", &issues))
+	assert.Empty(t, issues)
+
+	var apiErr map[string]string
+	assert.Equal(t, http.StatusNotFound, call("GET", "/api/items/999", "", &apiErr))
+	assert.Contains(t, apiErr["error"], "not found")
+	assert.Equal(t, http.StatusBadRequest, call("POST", "/api/items/001/move", `{"status":"nowhere"}`, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, call("PATCH", "/api/items/001", `{"fields":{"id":"002"}}`, &apiErr))
+	assert.Equal(t, http.StatusMethodNotAllowed, call("DELETE", "/api/items/001", "", &apiErr))
+
+	// Cross-origin writes and other host names are refused
+	request := func(host, origin, contentType string) int {
+		req := httptest.NewRequest("POST", "/api/items/001/comments", strings.NewReader(`{"text":"hi"}`))
+		req.Host = host
+		req.Header.Set("Content-Type", contentType)
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusUnsupportedMediaType, request("localhost:8080", "", "text/plain"))
+	assert.Equal(t, http.StatusForbidden, request("localhost:8080", "https://evil.example", "application/json"))
+	assert.Equal(t, http.StatusForbidden, request("evil.example:8080", "", "application/json"))
+	assert.Equal(t, http.StatusOK, request("127.0.0.1:8080", "http://127.0.0.1:8080", "application/json; charset=utf-8"))
+
+	// Every change was committed
+	status, err := runGit("status", "--porcelain", ".work")
+	require.NoError(t, err)
+	assert.Empty(t, status)
+}
+
+func TestChangedPaths(t *testing.T) {
+	before := map[string]string{".work/a.md": "1:1", ".work/b.md": "1:1"}
+	after := map[string]string{".work/a.md": "2:2", ".work/c.md": "1:1"}
+	assert.Equal(t, []string{".work/a.md", ".work/b.md", ".work/c.md"}, changedPaths(before, after))
+	assert.Empty(t, changedPaths(after, after))
+}
+
+func TestServeAllowedHost(t *testing.T) {
+	local := newServer(nil, "localhost:8080", false)
+	assert.True(t, local.allowedHost("localhost:8080"))
+	assert.True(t, local.allowedHost("[::1]:8080"))
+	assert.False(t, local.allowedHost("localhost:9090"))
+	assert.False(t, local.allowedHost("localhost"))
+	assert.False(t, local.allowedHost("192.168.1.5:8080"))
+
+	all := newServer(nil, ":8080", false)
+	assert.True(t, all.allowedHost("192.168.1.5:8080"))
+	assert.False(t, all.allowedHost("attacker.example:8080"))
+}
@@ -0,0 +1,438 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"kira/internal/config"
+	"kira/internal/templates"
+	"kira/internal/validation"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Work with work item templates",
+	Long: `Works with the templates in .work/templates that kira new uses for each kind.
+Templates may extend a base template and include partials from
+.work/templates/partials.`,
+}
+
+var templateRenderCmd = &cobra.Command{
+	Use:   "render <kind>",
+	Short: "Show a template with inheritance and partials expanded",
+	Long: `Renders the template for a kind with its base template and partials expanded.
+Inputs that are not given with --input are shown as <name> placeholders.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		inputValues, _ := cmd.Flags().GetStringToString("input")
+		content, err := renderTemplatePreview(cfg, args[0], inputValues)
+		if err != nil {
+			return err
+		}
+		fmt.Print(content)
+		return nil
+	},
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered templates",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		return listTemplates(cfg)
+	},
+}
+
+var templateShowCmd = &cobra.Command{
+	Use:   "show <kind>",
+	Short: "Show a template's source and inputs",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		return showTemplate(cfg, args[0])
+	},
+}
+
+var templateNewCmd = &cobra.Command{
+	Use:   "new <kind>",
+	Short: "Scaffold a template for a new kind and register it in kira.yml",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		from, _ := cmd.Flags().GetString("from")
+		return newTemplate(cfg, args[0], from)
+	},
+}
+
+var templateEditCmd = &cobra.Command{
+	Use:   "edit <kind>",
+	Short: "Open a template in $EDITOR and validate it afterwards",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		path, err := templatePath(cfg, args[0])
+		if err != nil {
+			return err
+		}
+		if err := openInEditor(path); err != nil {
+			return err
+		}
+		return validateTemplates(cfg, []string{args[0]})
+	},
+}
+
+var templateValidateCmd = &cobra.Command{
+	Use:   "validate [kind...]",
+	Short: "Check templates for malformed inputs, invalid front matter and missing required fields",
+	Long: `Parses each template, checks every input declaration, renders the template
+with sample values and with no values to make sure the front matter is valid
+YAML, and reports lint-required fields the template can never produce.
+Validates all registered templates when no kind is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		return validateTemplates(cfg, args)
+	},
+}
+
+var templateRemoveCmd = &cobra.Command{
+	Use:   "remove <kind>",
+	Short: "Unregister a kind and delete its template",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		keepFile, _ := cmd.Flags().GetBool("keep-file")
+		force, _ := cmd.Flags().GetBool("force")
+		return removeTemplate(cfg, args[0], keepFile, force)
+	},
+}
+
+func init() {
+	templateRenderCmd.Flags().StringToStringP("input", "i", nil, "Provide input values (e.g., --input estimate=3)")
+	templateNewCmd.Flags().String("from", "", "Copy the template of an existing kind instead of scaffolding one")
+	templateRemoveCmd.Flags().Bool("keep-file", false, "Only unregister the kind; leave the template file in place")
+	templateRemoveCmd.Flags().Bool("force", false, "Remove even if work items of this kind exist")
+
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateShowCmd)
+	templateCmd.AddCommand(templateRenderCmd)
+	templateCmd.AddCommand(templateNewCmd)
+	templateCmd.AddCommand(templateEditCmd)
+	templateCmd.AddCommand(templateValidateCmd)
+	templateCmd.AddCommand(templateRemoveCmd)
+}
+
+var kindNameRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+func sortedKinds(cfg *config.Config) []string {
+	var kinds []string
+	for k := range cfg.Templates {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func listTemplates(cfg *config.Config) error {
+	kinds := sortedKinds(cfg)
+	if len(kinds) == 0 {
+		fmt.Println("No templates registered in kira.yml.")
+		return nil
+	}
+	for _, kind := range kinds {
+		path := filepath.Join(".work", cfg.Templates[kind])
+		tmpl, err := templates.LoadTemplate(path)
+		if err != nil {
+			fmt.Printf("%-10s %s (error: %v)\n", kind, cfg.Templates[kind], err)
+			continue
+		}
+		detail := fmt.Sprintf("%d inputs, %s dialect", len(tmpl.Inputs), tmpl.Dialect)
+		if tmpl.Header.Extends != "" {
+			detail += ", extends " + tmpl.Header.Extends
+		}
+		fmt.Printf("%-10s %s (%s)\n", kind, cfg.Templates[kind], detail)
+	}
+
+	if len(cfg.TemplatePacks) > 0 {
+		var names []string
+		for name := range cfg.TemplatePacks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Println("\nTemplate packs:")
+		for _, name := range names {
+			pack := cfg.TemplatePacks[name]
+			fmt.Printf("%-10s %s from %s\n", name, pack.Version, pack.Source)
+		}
+	}
+	return nil
+}
+
+func showTemplate(cfg *config.Config, kind string) error {
+	path, err := templatePath(cfg, kind)
+	if err != nil {
+		return err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read template: %w", err)
+	}
+	tmpl, err := templates.LoadTemplate(path)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Template: %s\n", kind)
+	fmt.Printf("File: %s\n", path)
+	fmt.Printf("Dialect: %s\n", tmpl.Dialect)
+	if tmpl.Header.Extends != "" {
+		fmt.Printf("Extends: %s\n", tmpl.Header.Extends)
+	}
+	fmt.Println("Inputs:")
+	for _, input := range tmpl.Inputs {
+		fmt.Printf("  - %s (%s): %s\n", input.Name, input.Type, input.Description)
+	}
+	fmt.Printf("\n%s", content)
+	return nil
+}
+
+func newTemplate(cfg *config.Config, kind, from string) error {
+	if !kindNameRe.MatchString(kind) {
+		return fmt.Errorf("invalid kind '%s': use lowercase letters, digits and dashes", kind)
+	}
+	if _, exists := cfg.Templates[kind]; exists {
+		return fmt.Errorf("template '%s' is already registered", kind)
+	}
+
+	rel := filepath.ToSlash(filepath.Join("templates", "template."+kind+".md"))
+	path := filepath.Join(".work", rel)
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("%s already exists", path)
+	}
+
+	content := scaffoldTemplate(kind)
+	if from != "" {
+		fromPath, err := templatePath(cfg, from)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(fromPath)
+		if err != nil {
+			return fmt.Errorf("failed to read template: %w", err)
+		}
+		content = string(data)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write template: %w", err)
+	}
+	if err := config.SetValue([]string{"templates", kind}, rel); err != nil {
+		return fmt.Errorf("failed to register template: %w", err)
+	}
+
+	fmt.Printf("Created %s and registered kind '%s' in %s\n", path, kind, config.ConfigPath())
+	return nil
+}
+
+// scaffoldTemplate returns a starting point for a new kind, extending the
+// shared base when the workspace has one
+func scaffoldTemplate(kind string) string {
+	if _, err := os.Stat(filepath.Join(".work", "templates", "base.md")); err == nil {
+		return `---
+template:
+  extends: base
+  inputs:
+    - name: tags
+      type: strings
+      description: Tags
+---
+{{ define "body" }}
+## Description
+{{ prompt "description" "What is this ` + kind + ` about?" }}
+{{ end }}
+`
+	}
+	return `---
+template:
+  inputs:
+    - name: description
+      description: What is this ` + kind + ` about?
+---
+---
+id: {{ .id }}
+title: {{ .title }}
+status: {{ .status }}
+kind: {{ .kind }}
+created: {{ .created }}
+---
+
+# {{ .title }}
+
+## Description
+{{ .description }}
+`
+}
+
+func validateTemplates(cfg *config.Config, kinds []string) error {
+	if len(kinds) == 0 {
+		kinds = sortedKinds(cfg)
+	}
+
+	failed := 0
+	for _, kind := range kinds {
+		path, err := templatePath(cfg, kind)
+		if err != nil {
+			return err
+		}
+		var problems []string
+		tmpl, err := templates.LoadTemplate(path)
+		if err != nil {
+			problems = []string{err.Error()}
+		} else {
+			problems = tmpl.Check(cfg.Validation.RequiredFields)
+		}
+
+		if len(problems) == 0 {
+			fmt.Printf("%s: ok\n", kind)
+			continue
+		}
+		failed++
+		fmt.Printf("%s: %d problems\n", kind, len(problems))
+		for _, p := range problems {
+			fmt.Printf("  %s\n", p)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("template validation failed")
+	}
+	return nil
+}
+
+func removeTemplate(cfg *config.Config, kind string, keepFile, force bool) error {
+	path, err := templatePath(cfg, kind)
+	if err != nil {
+		return err
+	}
+
+	if !force {
+		files, err := getWorkItemFiles(".work")
+		if err != nil {
+			return fmt.Errorf("failed to get work item files: %w", err)
+		}
+		count := 0
+		for _, file := range files {
+			if item, err := validation.ParseWorkItemFile(file); err == nil && item.Kind == kind {
+				count++
+			}
+		}
+		if count > 0 {
+			return fmt.Errorf("%d work items of kind '%s' exist; use --force to remove the template anyway", count, kind)
+		}
+	}
+
+	if err := config.DeleteValue([]string{"templates", kind}); err != nil {
+		return fmt.Errorf("failed to unregister template: %w", err)
+	}
+	if !keepFile {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove template: %w", err)
+		}
+	}
+
+	fmt.Printf("Removed template '%s'\n", kind)
+	return nil
+}
+
+// openInEditor opens a file in $VISUAL or $EDITOR, falling back to vi
+func openInEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	cmd := exec.Command(parts[0], append(parts[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+	return nil
+}
+
+// templatePath returns the template file registered for a kind
+func templatePath(cfg *config.Config, kind string) (string, error) {
+	rel, ok := cfg.Templates[kind]
+	if !ok {
+		return "", fmt.Errorf("unknown template '%s' (available: %s)", kind, strings.Join(sortedKinds(cfg), ", "))
+	}
+	return filepath.Join(".work", rel), nil
+}
+
+// renderTemplatePreview expands a kind's template, filling every input that
+// has no value with a <name> placeholder so the full structure is visible
+func renderTemplatePreview(cfg *config.Config, kind string, inputValues map[string]string) (string, error) {
+	path, err := templatePath(cfg, kind)
+	if err != nil {
+		return "", err
+	}
+	tmpl, err := templates.LoadTemplate(path)
+	if err != nil {
+		return "", err
+	}
+
+	values := map[string]string{
+		"kind":    kind,
+		"status":  cfg.DefaultStatus,
+		"created": time.Now().Format("2006-01-02"),
+	}
+	for _, name := range templates.BuiltinValues {
+		if _, ok := values[name]; !ok {
+			values[name] = "<" + name + ">"
+		}
+	}
+	for _, input := range tmpl.Inputs {
+		values[input.Name] = "<" + input.Name + ">"
+	}
+	for k, v := range inputValues {
+		values[k] = v
+	}
+
+	return tmpl.Render(values, templates.DefaultRenderOptions())
+}
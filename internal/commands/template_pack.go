@@ -0,0 +1,540 @@
+package commands
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"kira/internal/config"
+	"kira/internal/templates"
+)
+
+var templateInstallCmd = &cobra.Command{
+	Use:   "install <source>",
+	Short: "Vendor a template pack from a directory, tarball or git repository",
+	Long: `Copies a template pack into .work/templates/<pack>/, registers its kinds in
+kira.yml and records the source and version under template_packs.
+
+The source is a local directory, a .tar, .tar.gz or .tgz file, or a git
+repository (a URL, a path ending in .git, or any source prefixed with git+).
+A pack.yml at the root of the pack may set its name, version and kinds;
+otherwise every template.<kind>.md file is a kind.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("name")
+		ref, _ := cmd.Flags().GetString("ref")
+		force, _ := cmd.Flags().GetBool("force")
+		return installTemplatePack(cfg, args[0], name, ref, force)
+	},
+}
+
+var templateUpdateCmd = &cobra.Command{
+	Use:   "update [pack...]",
+	Short: "Show upstream changes to installed template packs and merge them in",
+	Long: `Fetches each installed pack from its recorded source again and shows what
+changed upstream. Files you have not modified are replaced; files you have
+customised are merged three-way against the copy installed last time, leaving
+conflict markers where both sides changed the same lines.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadWorkspaceConfig()
+		if err != nil {
+			return err
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		source, _ := cmd.Flags().GetString("source")
+		return updateTemplatePacks(cfg, args, source, dryRun)
+	},
+}
+
+func init() {
+	templateInstallCmd.Flags().String("name", "", "Install under this pack name instead of the pack's own")
+	templateInstallCmd.Flags().String("ref", "", "Branch or tag to install from a git repository")
+	templateInstallCmd.Flags().Bool("force", false, "Reinstall over an existing pack and take over kinds registered elsewhere")
+	templateUpdateCmd.Flags().Bool("dry-run", false, "Show upstream changes without applying them")
+	templateUpdateCmd.Flags().String("source", "", "Fetch from this source instead of the recorded one (one pack only)")
+
+	templateCmd.AddCommand(templateInstallCmd)
+	templateCmd.AddCommand(templateUpdateCmd)
+}
+
+func installTemplatePack(cfg *config.Config, source, name, ref string, force bool) error {
+	dir, cleanup, err := fetchTemplatePack(source, ref)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	pack, err := templates.ReadPack(dir)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		name = pack.Name
+	}
+	if !kindNameRe.MatchString(name) || name == templates.PartialsDir {
+		return fmt.Errorf("invalid pack name '%s': use --name with lowercase letters, digits and dashes", name)
+	}
+
+	dest := filepath.Join(".work", "templates", name)
+	if _, err := os.Stat(dest); err == nil {
+		if !force {
+			return fmt.Errorf("template pack '%s' is already installed; use 'kira template update %s' or --force", name, name)
+		}
+		if err := os.RemoveAll(dest); err != nil {
+			return fmt.Errorf("failed to remove existing pack: %w", err)
+		}
+	}
+
+	var conflicts []string
+	for _, kind := range pack.SortedKinds() {
+		if existing, ok := cfg.Templates[kind]; ok && existing != packTemplatePath(name, pack.Kinds[kind]) {
+			conflicts = append(conflicts, fmt.Sprintf("%s (registered to %s)", kind, existing))
+		}
+	}
+	if len(conflicts) > 0 && !force {
+		return fmt.Errorf("kinds already registered: %s; use --force to register the pack's templates instead", strings.Join(conflicts, ", "))
+	}
+
+	if err := copyPackFiles(dir, dest); err != nil {
+		return err
+	}
+	if err := copyPackFiles(dir, filepath.Join(dest, templates.UpstreamDir)); err != nil {
+		return err
+	}
+
+	version := packVersion(pack, dir)
+	if err := registerTemplatePack(name, config.TemplatePack{Source: source, Ref: ref, Version: version, Kinds: pack.SortedKinds()}, pack, nil); err != nil {
+		return err
+	}
+
+	fmt.Printf("Installed template pack %s %s into %s (kinds: %s)\n", name, version, dest, strings.Join(pack.SortedKinds(), ", "))
+	return nil
+}
+
+func updateTemplatePacks(cfg *config.Config, names []string, source string, dryRun bool) error {
+	if len(names) == 0 {
+		for name := range cfg.TemplatePacks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	if len(names) == 0 {
+		fmt.Println("No template packs installed.")
+		return nil
+	}
+	if source != "" && len(names) > 1 {
+		return fmt.Errorf("--source can only be used when updating a single pack")
+	}
+
+	var conflicted []string
+	for _, name := range names {
+		files, err := updateTemplatePack(cfg, name, source, dryRun)
+		if err != nil {
+			return err
+		}
+		conflicted = append(conflicted, files...)
+	}
+
+	if len(conflicted) > 0 {
+		return fmt.Errorf("merge conflicts in %s; resolve the conflict markers and run 'kira template validate'", strings.Join(conflicted, ", "))
+	}
+	return nil
+}
+
+// updateTemplatePack merges upstream changes into one installed pack and
+// returns the files left with conflicts
+func updateTemplatePack(cfg *config.Config, name, source string, dryRun bool) ([]string, error) {
+	record, ok := cfg.TemplatePacks[name]
+	if !ok {
+		return nil, fmt.Errorf("template pack '%s' is not installed", name)
+	}
+	if source == "" {
+		source = record.Source
+	}
+
+	dir, cleanup, err := fetchTemplatePack(source, record.Ref)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	pack, err := templates.ReadPack(dir)
+	if err != nil {
+		return nil, err
+	}
+	version := packVersion(pack, dir)
+
+	dest := filepath.Join(".work", "templates", name)
+	upstream := filepath.Join(dest, templates.UpstreamDir)
+	incomingFiles, err := templates.PackFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	baseFiles, err := templates.PackFiles(upstream)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	fmt.Printf("Template pack %s: %s -> %s\n", name, record.Version, version)
+
+	var conflicted []string
+	changes := 0
+	for _, file := range unionStrings(baseFiles, incomingFiles) {
+		basePath := filepath.Join(upstream, file)
+		localPath := filepath.Join(dest, file)
+		incomingPath := filepath.Join(dir, file)
+
+		base, hasBase := readOptional(basePath)
+		local, hasLocal := readOptional(localPath)
+		incoming, hasIncoming := readOptional(incomingPath)
+
+		if hasBase && hasIncoming && bytes.Equal(base, incoming) {
+			continue
+		}
+		changes++
+
+		diff, err := fileDiff(basePath, incomingPath, hasBase, hasIncoming)
+		if err != nil {
+			return nil, err
+		}
+		fmt.Print(diff)
+
+		var action string
+		switch {
+		case !hasIncoming && (!hasLocal || bytes.Equal(local, base)):
+			action = "removed"
+			if !dryRun {
+				if err := os.Remove(localPath); err != nil && !os.IsNotExist(err) {
+					return nil, err
+				}
+			}
+		case !hasIncoming:
+			action = "kept (removed upstream, modified locally)"
+		case hasBase && !hasLocal:
+			action = "skipped (deleted locally)"
+		case !hasLocal || bytes.Equal(local, base) || bytes.Equal(local, incoming):
+			action = "updated"
+			if !hasLocal {
+				action = "added"
+			}
+			if !dryRun {
+				if err := writePackFile(localPath, incoming); err != nil {
+					return nil, err
+				}
+			}
+		default:
+			merged, conflicts, err := mergeFile(localPath, basePath, incomingPath, hasBase)
+			if err != nil {
+				return nil, err
+			}
+			action = "merged with local changes"
+			if conflicts > 0 {
+				action = fmt.Sprintf("merged with %d conflicts", conflicts)
+				conflicted = append(conflicted, localPath)
+			}
+			if !dryRun {
+				if err := writePackFile(localPath, merged); err != nil {
+					return nil, err
+				}
+			}
+		}
+		fmt.Printf("  %s: %s\n", file, action)
+	}
+
+	if changes == 0 {
+		fmt.Println("  already up to date")
+	}
+	if dryRun {
+		return nil, nil
+	}
+
+	if err := os.RemoveAll(upstream); err != nil {
+		return nil, fmt.Errorf("failed to refresh upstream copy: %w", err)
+	}
+	if err := copyPackFiles(dir, upstream); err != nil {
+		return nil, err
+	}
+
+	record.Source = source
+	record.Version = version
+	oldKinds := record.Kinds
+	record.Kinds = pack.SortedKinds()
+	if err := registerTemplatePack(name, record, pack, oldKinds); err != nil {
+		return nil, err
+	}
+	return conflicted, nil
+}
+
+// registerTemplatePack points the pack's kinds at its vendored templates,
+// drops kinds the pack no longer ships and records the pack in kira.yml
+func registerTemplatePack(name string, record config.TemplatePack, pack templates.Pack, oldKinds []string) error {
+	for _, kind := range oldKinds {
+		if _, ok := pack.Kinds[kind]; !ok {
+			if err := config.DeleteValue([]string{"templates", kind}); err != nil {
+				return fmt.Errorf("failed to unregister kind %s: %w", kind, err)
+			}
+		}
+	}
+	for _, kind := range pack.SortedKinds() {
+		if err := config.SetValue([]string{"templates", kind}, packTemplatePath(name, pack.Kinds[kind])); err != nil {
+			return fmt.Errorf("failed to register kind %s: %w", kind, err)
+		}
+	}
+	if err := config.SetValue([]string{"template_packs", name}, record); err != nil {
+		return fmt.Errorf("failed to record template pack: %w", err)
+	}
+	return nil
+}
+
+func packTemplatePath(pack, file string) string {
+	return filepath.ToSlash(filepath.Join("templates", pack, file))
+}
+
+// fetchTemplatePack makes a pack source available as a local directory. Git
+// repositories, local ones included, are cloned so only committed files are
+// installed. The cleanup function removes any temporary copy.
+func fetchTemplatePack(source, ref string) (string, func(), error) {
+	noop := func() {}
+
+	info, statErr := os.Stat(source)
+	isLocalRepo := statErr == nil && info.IsDir() && isGitRepoRoot(source)
+	switch {
+	case isGitSource(source) || isLocalRepo:
+		tmp, err := os.MkdirTemp("", "kira-pack-")
+		if err != nil {
+			return "", noop, err
+		}
+		cleanup := func() { os.RemoveAll(tmp) }
+		args := []string{"clone", "--quiet", "--depth", "1"}
+		if ref != "" {
+			args = append(args, "--branch", ref)
+		}
+		url := strings.TrimPrefix(source, "git+")
+		if _, err := os.Stat(url); err == nil {
+			// --depth only applies to local clones through file://
+			if abs, err := filepath.Abs(url); err == nil {
+				url = "file://" + filepath.ToSlash(abs)
+			}
+		}
+		if _, err := runGit(append(args, url, tmp)...); err != nil {
+			cleanup()
+			return "", noop, fmt.Errorf("failed to fetch template pack: %w", err)
+		}
+		return tmp, cleanup, nil
+
+	case isTarball(source):
+		tmp, err := os.MkdirTemp("", "kira-pack-")
+		if err != nil {
+			return "", noop, err
+		}
+		cleanup := func() { os.RemoveAll(tmp) }
+		if err := extractTarball(source, tmp); err != nil {
+			cleanup()
+			return "", noop, fmt.Errorf("failed to extract template pack: %w", err)
+		}
+		return singleSubdir(tmp), cleanup, nil
+
+	case statErr == nil && info.IsDir():
+		if ref != "" {
+			return "", noop, fmt.Errorf("--ref needs a git repository, but %s is a plain directory", source)
+		}
+		return source, noop, nil
+	}
+
+	if statErr != nil {
+		return "", noop, fmt.Errorf("template pack source %s not found", source)
+	}
+	return "", noop, fmt.Errorf("unsupported template pack source %s: expected a directory, tarball or git repository", source)
+}
+
+func isGitSource(source string) bool {
+	return strings.HasPrefix(source, "git+") ||
+		strings.HasPrefix(source, "git@") ||
+		strings.Contains(source, "://") ||
+		strings.HasSuffix(source, ".git")
+}
+
+func isTarball(source string) bool {
+	for _, ext := range []string{".tar", ".tar.gz", ".tgz"} {
+		if strings.HasSuffix(source, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// packVersion is the version from pack.yml, else the git description of a
+// cloned source, else "unversioned". A plain directory is not described, as
+// git would describe whatever repository encloses it.
+func packVersion(pack templates.Pack, dir string) string {
+	if pack.Version != "" {
+		return pack.Version
+	}
+	if !isGitRepoRoot(dir) {
+		return "unversioned"
+	}
+	if out, err := runGit("-C", dir, "describe", "--tags", "--always"); err == nil {
+		if v := strings.TrimSpace(out); v != "" {
+			return v
+		}
+	}
+	return "unversioned"
+}
+
+func extractTarball(path, dest string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if !strings.HasSuffix(path, ".tar") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, filepath.FromSlash(hdr.Name))
+		if rel, err := filepath.Rel(dest, target); err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("unsafe path in archive: %s", hdr.Name)
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return err
+			}
+			if err := writePackFile(target, data); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// singleSubdir descends into the only folder of an extracted archive, the
+// usual layout of release tarballs
+func singleSubdir(dir string) string {
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) != 1 || !entries[0].IsDir() {
+		return dir
+	}
+	return filepath.Join(dir, entries[0].Name())
+}
+
+func copyPackFiles(src, dest string) error {
+	files, err := templates.PackFiles(src)
+	if err != nil {
+		return fmt.Errorf("failed to read template pack: %w", err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(filepath.Join(src, file))
+		if err != nil {
+			return err
+		}
+		if err := writePackFile(filepath.Join(dest, file), data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writePackFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func readOptional(path string) ([]byte, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// fileDiff returns a unified diff between two files, either of which may be
+// missing
+func fileDiff(oldPath, newPath string, hasOld, hasNew bool) (string, error) {
+	if !hasOld {
+		oldPath = os.DevNull
+	}
+	if !hasNew {
+		newPath = os.DevNull
+	}
+	out, err := exec.Command("git", "diff", "--no-index", "--no-color", "--", oldPath, newPath).Output()
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		// exit status 1 means the files differ
+		return string(out), nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to diff %s: %w", newPath, err)
+	}
+	return string(out), nil
+}
+
+// mergeFile merges upstream changes (base -> incoming) into local with
+// git merge-file and returns the result and the number of conflicts
+func mergeFile(localPath, basePath, incomingPath string, hasBase bool) ([]byte, int, error) {
+	if !hasBase {
+		basePath = os.DevNull
+	}
+	cmd := exec.Command("git", "merge-file", "-p", "-L", "local", "-L", "installed", "-L", "upstream", localPath, basePath, incomingPath)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		code := exitErr.ExitCode()
+		// a positive exit status below 128 is the number of conflicts
+		if code > 0 && code < 128 {
+			return out, code, nil
+		}
+		return nil, 0, fmt.Errorf("git merge-file %s: %s", localPath, strings.TrimSpace(stderr.String()))
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("git merge-file %s: %w", localPath, err)
+	}
+	return out, 0, nil
+}
+
+func unionStrings(a, b []string) []string {
+	seen := make(map[string]bool)
+	var all []string
+	for _, s := range append(append([]string{}, a...), b...) {
+		if !seen[s] {
+			seen[s] = true
+			all = append(all, s)
+		}
+	}
+	sort.Strings(all)
+	return all
+}
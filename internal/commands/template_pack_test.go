@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+	"kira/internal/templates"
+)
+
+func TestInstallAndUpdateTemplatePack(t *testing.T) {
+	tmpDir := t.TempDir()
+	packDir := filepath.Join(tmpDir, "pack")
+	workDir := filepath.Join(tmpDir, "repo")
+	require.NoError(t, os.MkdirAll(packDir, 0755))
+	require.NoError(t, initializeWorkspace(workDir))
+	os.Chdir(workDir)
+	defer os.Chdir("/")
+
+	story := "---\ntemplate:\n  inputs: []\n---\n---\nid: {{ .id }}\ntitle: {{ .title }}\nstatus: {{ .status }}\nkind: {{ .kind }}\ncreated: {{ .created }}\n---\n\n## Story\n\n## Notes\n"
+	require.NoError(t, os.WriteFile(filepath.Join(packDir, "pack.yml"), []byte("name: acme\nversion: 1.0.0\nkinds:\n  story: template.story.md\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(packDir, "template.story.md"), []byte(story), 0644))
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	require.NoError(t, installTemplatePack(cfg, packDir, "", "", false))
+
+	cfg, err = config.LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "templates/acme/template.story.md", cfg.Templates["story"])
+	assert.Equal(t, "1.0.0", cfg.TemplatePacks["acme"].Version)
+	assert.Error(t, installTemplatePack(cfg, packDir, "", "", false), "already installed")
+
+	// Customise locally, then change a different line upstream
+	local := ".work/templates/acme/template.story.md"
+	require.NoError(t, os.WriteFile(local, []byte(strings.Replace(story, "## Notes", "## Team Notes", 1)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(packDir, "template.story.md"), []byte(strings.Replace(story, "## Story", "## User Story", 1)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(packDir, "pack.yml"), []byte("name: acme\nversion: 1.1.0\nkinds:\n  story: template.story.md\n"), 0644))
+
+	require.NoError(t, updateTemplatePacks(cfg, nil, "", false))
+	content, err := os.ReadFile(local)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "## User Story")
+	assert.Contains(t, string(content), "## Team Notes")
+
+	cfg, err = config.LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "1.1.0", cfg.TemplatePacks["acme"].Version)
+
+	// Both sides change the same line
+	require.NoError(t, os.WriteFile(filepath.Join(packDir, "template.story.md"), []byte(strings.Replace(story, "## Notes", "## Remarks", 1)), 0644))
+	err = updateTemplatePacks(cfg, []string{"acme"}, "", false)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "merge conflicts")
+	content, err = os.ReadFile(local)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "<<<<<<< local")
+}
+
+func TestFetchTemplatePackSources(t *testing.T) {
+	tmpDir := t.TempDir()
+	writePack := func(dir string) {
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "template.story.md"), []byte("## Story\n"), 0644))
+	}
+	git := func(dir string, args ...string) {
+		_, err := runGit(append([]string{"-C", dir, "-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		require.NoError(t, err)
+	}
+
+	// A plain folder inside another repository is copied and not described
+	outer := filepath.Join(tmpDir, "outer")
+	writePack(filepath.Join(outer, "packs", "acme"))
+	git(outer, "init", "--quiet")
+	git(outer, "add", "-A")
+	git(outer, "commit", "--quiet", "-m", "init")
+	git(outer, "tag", "v9.9.9")
+	dir, cleanup, err := fetchTemplatePack(filepath.Join(outer, "packs", "acme"), "")
+	require.NoError(t, err)
+	defer cleanup()
+	assert.Equal(t, filepath.Join(outer, "packs", "acme"), dir)
+	assert.Equal(t, "unversioned", packVersion(templates.Pack{}, dir))
+	_, _, err = fetchTemplatePack(filepath.Join(outer, "packs", "acme"), "v1")
+	assert.Error(t, err, "--ref needs a repository")
+
+	// A local repository is cloned without its uncommitted changes
+	repo := filepath.Join(tmpDir, "repo")
+	writePack(repo)
+	git(repo, "init", "--quiet")
+	git(repo, "add", "-A")
+	git(repo, "commit", "--quiet", "-m", "init")
+	git(repo, "tag", "v1.2.0")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "template.draft.md"), []byte("## Draft\n"), 0644))
+	dir, cleanup, err = fetchTemplatePack(repo, "")
+	require.NoError(t, err)
+	defer cleanup()
+	assert.NotEqual(t, repo, dir)
+	assert.FileExists(t, filepath.Join(dir, "template.story.md"))
+	assert.NoFileExists(t, filepath.Join(dir, "template.draft.md"))
+	assert.Equal(t, "v1.2.0", packVersion(templates.Pack{}, dir))
+}
@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestTemplateNewAndRemove(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, initializeWorkspace(tmpDir))
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+
+	original, err := os.ReadFile("kira.yml")
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile("kira.yml", append([]byte("# team settings\n"), original...), 0644))
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	require.NoError(t, newTemplate(cfg, "bug", ""))
+	assert.FileExists(t, ".work/templates/template.bug.md")
+
+	cfg, err = config.LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "templates/template.bug.md", cfg.Templates["bug"])
+	require.NoError(t, validateTemplates(cfg, []string{"bug"}))
+
+	content, err := os.ReadFile("kira.yml")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "# team settings", "kira.yml is edited in place")
+
+	assert.Error(t, newTemplate(cfg, "bug", ""), "kind already registered")
+	assert.Error(t, newTemplate(cfg, "Bad Kind", ""))
+
+	require.NoError(t, removeTemplate(cfg, "bug", false, false))
+	assert.NoFileExists(t, ".work/templates/template.bug.md")
+	cfg, err = config.LoadConfig()
+	require.NoError(t, err)
+	assert.NotContains(t, cfg.Templates, "bug")
+}
@@ -1,13 +1,21 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"time"
+
+	"kira/internal/archive"
+	"kira/internal/config"
+	"kira/internal/validation"
 )
 
+// errWorkItemNotFound is returned when no work item has the requested ID
+var errWorkItemNotFound = errors.New("not found")
+
 // findWorkItemFile searches for a work item file by ID
 func findWorkItemFile(workItemID string) (string, error) {
 	var foundPath string
@@ -22,7 +30,7 @@ func findWorkItemFile(workItemID string) (string, error) {
 		}
 		
 		// Check if this is a work item file with the matching ID
-		if strings.HasSuffix(path, ".md") && !strings.Contains(path, "template") && !strings.HasSuffix(path, "IDEAS.md") {
+		if strings.HasSuffix(path, ".md") && !strings.Contains(path, "template") && !strings.HasSuffix(path, "IDEAS.md") && !archive.IsBundle(path) {
 			// Read the file to check the ID
 			content, err := os.ReadFile(path)
 			if err != nil {
@@ -44,7 +52,7 @@ func findWorkItemFile(workItemID string) (string, error) {
 	}
 	
 	if foundPath == "" {
-		return "", fmt.Errorf("work item with ID %s not found", workItemID)
+		return "", fmt.Errorf("work item with ID %s %w", workItemID, errWorkItemNotFound)
 	}
 	
 	return foundPath, nil
@@ -81,7 +89,7 @@ func getWorkItemFiles(sourcePath string) ([]string, error) {
 			return nil
 		}
 		
-		if strings.HasSuffix(path, ".md") && !strings.Contains(path, "template") {
+		if strings.HasSuffix(path, ".md") && !strings.Contains(path, "template") && !archive.IsBundle(path) {
 			files = append(files, path)
 		}
 		
@@ -91,31 +99,109 @@ func getWorkItemFiles(sourcePath string) ([]string, error) {
 	return files, err
 }
 
-// archiveWorkItems archives work items to the archive directory
-func archiveWorkItems(workItems []string, sourcePath string) (string, error) {
-	// Create archive directory
-	date := time.Now().Format("2006-01-02")
-	archiveDir := filepath.Join(".work", "z_archive", date, filepath.Base(sourcePath))
-	
+// archiveBatch describes one release or abandonment being archived
+type archiveBatch struct {
+	Type    string // "release" or "abandon"
+	Name    string // batch folder name: the source folder or a single item's ID
+	Source  string // folder the items were taken from
+	Version string
+	Reason  string
+	Date    time.Time // archive date; now when zero
+}
+
+// archiveWorkItems moves work items into a new batch folder under
+// <archive>/<date>/<name>, keeping their paths relative to the batch source.
+// A numeric suffix is added when the batch folder already exists so repeated
+// releases never overwrite each other. Tracked files are moved with git mv so
+// their history can be followed across archival.
+func archiveWorkItems(cfg *config.Config, workItems []string, batch archiveBatch) (string, error) {
+	archiveFolder, ok := cfg.StatusFolders["archived"]
+	if !ok || archiveFolder == "" {
+		return "", fmt.Errorf("no archived folder in status_folders")
+	}
+	now := batch.Date
+	if now.IsZero() {
+		now = time.Now()
+	}
+	dateDir := filepath.Join(".work", archiveFolder, now.Format(cfg.Release.ArchiveDateFormat))
+
+	archiveDir := filepath.Join(dateDir, batch.Name)
+	for n := 2; ; n++ {
+		if _, err := os.Stat(archiveDir); os.IsNotExist(err) {
+			break
+		}
+		archiveDir = filepath.Join(dateDir, fmt.Sprintf("%s-%d", batch.Name, n))
+	}
+
 	if err := os.MkdirAll(archiveDir, 0755); err != nil {
 		return "", fmt.Errorf("failed to create archive directory: %w", err)
 	}
-	
-	// Copy work items to archive
+
+	manifest := archive.Manifest{
+		Type:    batch.Type,
+		Version: batch.Version,
+		Reason:  batch.Reason,
+		Date:    now.UTC().Format(time.RFC3339),
+		Source:  batch.Source,
+	}
+
+	useGit := isGitRepo()
 	for _, workItem := range workItems {
-		filename := filepath.Base(workItem)
-		archivePath := filepath.Join(archiveDir, filename)
-		
-		content, err := os.ReadFile(workItem)
-		if err != nil {
-			return "", fmt.Errorf("failed to read work item: %w", err)
+		rel, err := filepath.Rel(batch.Source, workItem)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			rel = filepath.Base(workItem)
 		}
-		
-		if err := os.WriteFile(archivePath, content, 0644); err != nil {
-			return "", fmt.Errorf("failed to write to archive: %w", err)
+		archivePath := filepath.Join(archiveDir, rel)
+		if err := os.MkdirAll(filepath.Dir(archivePath), 0755); err != nil {
+			return "", fmt.Errorf("failed to create archive directory: %w", err)
+		}
+
+		item := archive.ManifestItem{From: workItem, Path: archivePath}
+		if wi, err := validation.ParseWorkItemFile(workItem); err == nil {
+			item.ID, item.Title, item.Kind = wi.ID, wi.Title, wi.Kind
+		}
+
+		if err := moveFile(workItem, archivePath, useGit); err != nil {
+			return "", fmt.Errorf("failed to archive %s: %w", workItem, err)
 		}
+		manifest.Items = append(manifest.Items, item)
 	}
-	
+
+	if err := archive.WriteManifest(archiveDir, manifest); err != nil {
+		return "", fmt.Errorf("failed to write archive manifest: %w", err)
+	}
+
 	return archiveDir, nil
 }
 
+// moveFile renames src to dst, using git mv for tracked files when useGit is set
+func moveFile(src, dst string, useGit bool) error {
+	if useGit && isTrackedByGit(src) {
+		if _, err := runGit("mv", src, dst); err == nil {
+			return nil
+		}
+	}
+	return os.Rename(src, dst)
+}
+
+// containsString reports whether list contains s
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// loadWorkspaceConfig checks for a workspace and loads its kira.yml
+func loadWorkspaceConfig() (*config.Config, error) {
+	if err := checkWorkDir(); err != nil {
+		return nil, err
+	}
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	return cfg, nil
+}
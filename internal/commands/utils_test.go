@@ -1,12 +1,16 @@
 package commands
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"kira/internal/config"
 )
 
 func TestFindWorkItemFile(t *testing.T) {
@@ -130,7 +134,7 @@ func TestArchiveWorkItems(t *testing.T) {
 		defer os.Chdir("/")
 		
 		// Create .work directory structure
-		os.MkdirAll(".work", 0755)
+		os.MkdirAll(".work/4_done/v2", 0755)
 		
 		// Create work item files
 		workItem1 := `---
@@ -145,32 +149,80 @@ title: Test Feature 2
 ---
 # Test Feature 2
 `
-		os.WriteFile("work-item1.md", []byte(workItem1), 0644)
-		os.WriteFile("work-item2.md", []byte(workItem2), 0644)
+		os.WriteFile(".work/4_done/work-item1.md", []byte(workItem1), 0644)
+		os.WriteFile(".work/4_done/v2/work-item2.md", []byte(workItem2), 0644)
 		
-		workItems := []string{"work-item1.md", "work-item2.md"}
+		workItems := []string{".work/4_done/work-item1.md", ".work/4_done/v2/work-item2.md"}
 		
 		// Archive work items
-		archivePath, err := archiveWorkItems(workItems, "source-dir")
+		cfg := &config.DefaultConfig
+		date := time.Date(2024, 3, 9, 23, 59, 59, 0, time.Local)
+		archivePath, err := archiveWorkItems(cfg, workItems, archiveBatch{Type: "release", Name: "4_done", Source: ".work/4_done", Version: "v1", Date: date})
 		require.NoError(t, err)
 		
 		// Check that archive directory was created
 		assert.DirExists(t, archivePath)
+		assert.Equal(t, filepath.Join(".work", "z_archive", date.Format(cfg.Release.ArchiveDateFormat), "4_done"), archivePath)
 		
-		// Check that work items were copied to archive
+		// Check that work items were moved to archive, keeping subfolders
 		archivedFile1 := filepath.Join(archivePath, "work-item1.md")
-		archivedFile2 := filepath.Join(archivePath, "work-item2.md")
+		archivedFile2 := filepath.Join(archivePath, "v2", "work-item2.md")
 		
 		assert.FileExists(t, archivedFile1)
 		assert.FileExists(t, archivedFile2)
+		assert.NoFileExists(t, workItems[0])
+		assert.NoFileExists(t, workItems[1])
 		
 		// Check that content was preserved
 		content1, err := os.ReadFile(archivedFile1)
 		require.NoError(t, err)
 		assert.Contains(t, string(content1), "Test Feature 1")
 		
-		content2, err := os.ReadFile(archivedFile2)
+		// Check the manifest records the batch
+		manifest, err := os.ReadFile(filepath.Join(archivePath, "manifest.yml"))
 		require.NoError(t, err)
-		assert.Contains(t, string(content2), "Test Feature 2")
+		assert.Contains(t, string(manifest), "type: release")
+		assert.Contains(t, string(manifest), "version: v1")
+		assert.Contains(t, string(manifest), "id: \"002\"")
+	})
+
+	t.Run("same-day batches from the same folder do not collide", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		os.Chdir(tmpDir)
+		defer os.Chdir("/")
+
+		os.MkdirAll(".work/4_done", 0755)
+		cfg := config.DefaultConfig
+		cfg.Release.ArchiveDateFormat = "2006/01"
+
+		var paths []string
+		for i := 0; i < 2; i++ {
+			os.WriteFile(".work/4_done/item.md", []byte(fmt.Sprintf("---\nid: 00%d\n---\n", i+1)), 0644)
+			archivePath, err := archiveWorkItems(&cfg, []string{".work/4_done/item.md"}, archiveBatch{Type: "release", Name: "4_done", Source: ".work/4_done", Date: time.Date(2024, 3, 31, 0, 0, 0, 0, time.Local)})
+			require.NoError(t, err)
+			paths = append(paths, archivePath)
+		}
+
+		assert.NotEqual(t, paths[0], paths[1])
+		assert.True(t, strings.HasSuffix(paths[1], "4_done-2"))
+		assert.Contains(t, paths[0], "2024/03")
+		content, err := os.ReadFile(filepath.Join(paths[0], "item.md"))
+		require.NoError(t, err)
+		assert.Contains(t, string(content), "id: 001")
+	})
+
+	t.Run("a config without an archived folder is an error", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		os.Chdir(tmpDir)
+		defer os.Chdir("/")
+
+		os.MkdirAll(".work/4_done", 0755)
+		os.WriteFile(".work/4_done/item.md", []byte("---\nid: 001\n---\n"), 0644)
+		cfg := config.DefaultConfig
+		cfg.StatusFolders = map[string]string{"done": "4_done"}
+
+		_, err := archiveWorkItems(&cfg, []string{".work/4_done/item.md"}, archiveBatch{Type: "release", Name: "4_done", Source: ".work/4_done"})
+		assert.ErrorContains(t, err, "archived")
+		assert.FileExists(t, ".work/4_done/item.md")
 	})
 }
@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"kira/internal/config"
+	"kira/internal/validation"
+)
+
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Keep work items consistent as files change",
+	Long: `Watches .work and reacts to every work item that changes on disk:
+
+  - editing status: by hand moves the file to the matching folder
+  - moving a file to another status folder sets its status: to match
+  - updated: is stamped with the time of the change
+  - the item is linted and any errors are printed
+
+With --auto-save, the changes are committed as kira save does once nothing
+has changed for that long. File notifications are used where the platform
+has them; elsewhere, or with --poll, .work is scanned every --interval.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := checkWorkDir(); err != nil {
+			return err
+		}
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		poll, _ := cmd.Flags().GetBool("poll")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		autoSave, _ := cmd.Flags().GetDuration("auto-save")
+		if autoSave > 0 && !isGitRepo() {
+			return fmt.Errorf("--auto-save needs a git repository")
+		}
+		return runWatch(cfg, poll, interval, autoSave)
+	},
+}
+
+func init() {
+	watchCmd.Flags().Bool("poll", false, "Scan for changes instead of using file notifications")
+	watchCmd.Flags().Duration("interval", time.Second, "How often to scan when polling")
+	watchCmd.Flags().Duration("auto-save", 0, "Commit changes once nothing has changed for this long, e.g. 30s")
+}
+
+// errNotifyUnsupported is returned by notifyWorkChanges on platforms
+// without file notifications
+var errNotifyUnsupported = errors.New("file notifications are not supported on this platform")
+
+func runWatch(cfg *config.Config, poll bool, interval, autoSave time.Duration) error {
+	w := newWorkWatcher(cfg)
+	events := make(chan []string)
+	stop := make(chan struct{})
+	defer close(stop)
+	send := func(paths []string) {
+		select {
+		case events <- paths:
+		case <-stop:
+		}
+	}
+	go func() {
+		if !poll {
+			err := notifyWorkChanges(".work", stop, send)
+			if err == nil {
+				return
+			}
+			fmt.Fprintf(os.Stderr, "Falling back to polling every %s: %v\n", interval, err)
+		}
+		pollWorkChanges(interval, stop, send)
+	}()
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	fmt.Println("Watching .work for changes (Ctrl-C to stop)")
+	var saveTimer <-chan time.Time
+	for {
+		select {
+		case paths := <-events:
+			if w.handle(paths) && autoSave > 0 {
+				saveTimer = time.After(autoSave)
+			}
+		case <-saveTimer:
+			saveTimer = nil
+			w.save()
+		case <-interrupt:
+			if saveTimer != nil {
+				w.save()
+			}
+			return nil
+		}
+	}
+}
+
+// workWatcher remembers the work items as it last saw or wrote them, to
+// tell real edits from its own and a moved file from an edited one
+type workWatcher struct {
+	cfg      *config.Config
+	contents map[string]string // path -> content
+	paths    map[string]string // ID -> path
+}
+
+func newWorkWatcher(cfg *config.Config) *workWatcher {
+	w := &workWatcher{cfg: cfg, paths: make(map[string]string)}
+	w.rescan()
+	return w
+}
+
+// rescan records every work item as it is now
+func (w *workWatcher) rescan() {
+	w.contents = make(map[string]string)
+	filepath.Walk(".work", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || watchedStatus(w.cfg, path) == "" {
+			return nil
+		}
+		if content, err := os.ReadFile(path); err == nil {
+			w.contents[path] = string(content)
+			if wi, err := validation.ParseWorkItemFile(path); err == nil && wi.ID != "" {
+				w.paths[wi.ID] = path
+			}
+		}
+		return nil
+	})
+}
+
+// watchedStatus is the status of the folder holding a work item file, or
+// "" for files the watcher leaves alone: templates, IDEAS.md, the archive
+// and anything outside a status folder
+func watchedStatus(cfg *config.Config, path string) string {
+	path = filepath.Clean(path)
+	if !strings.HasSuffix(path, ".md") || strings.HasSuffix(path, "IDEAS.md") || filepath.Dir(filepath.Dir(path)) != ".work" {
+		return ""
+	}
+	status := folderStatus(cfg, path, "")
+	if status == "archived" {
+		return ""
+	}
+	return status
+}
+
+// handle reacts to changed paths and reports whether any work item changed
+func (w *workWatcher) handle(paths []string) bool {
+	changed := false
+	for _, path := range paths {
+		path = filepath.Clean(path)
+		if watchedStatus(w.cfg, path) == "" {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			delete(w.contents, path)
+			continue
+		}
+		if string(content) == w.contents[path] {
+			continue // unchanged, or written by the watcher
+		}
+		changed = true
+		if err := w.handleFile(path); err != nil {
+			fmt.Printf("%s: %v\n", path, err)
+		}
+	}
+	return changed
+}
+
+// handleFile brings a changed work item's folder and status in line,
+// stamps it and lints it
+func (w *workWatcher) handleFile(path string) error {
+	wi, err := validation.ParseWorkItemFile(path)
+	if err != nil || wi.ID == "" {
+		w.remember(path)
+		w.lint(path)
+		return nil
+	}
+	folder := watchedStatus(w.cfg, path)
+	previous, known := w.paths[wi.ID]
+	moved := known && previous != path
+
+	switch {
+	case wi.Status == folder:
+		fmt.Printf("%s: updated\n", wi.ID)
+	case moved:
+		if err := updateItemFields(w.cfg, os.Stdout, wi.ID, map[string]interface{}{"status": folder}); err != nil {
+			return err
+		}
+		fmt.Printf("%s: status set to %s to match its folder\n", wi.ID, folder)
+	case w.cfg.StatusFolders[wi.Status] != "":
+		if err := moveItem(w.cfg, os.Stdout, wi.ID, wi.Status); err != nil {
+			return err
+		}
+		if path, err = findWorkItemFile(wi.ID); err != nil {
+			return err
+		}
+	default:
+		fmt.Printf("%s: updated\n", wi.ID) // lint reports the unknown status
+	}
+
+	if err := updateFileTimestamp(path, time.Now().UTC().Format(updatedTimeFormat)); err != nil {
+		return fmt.Errorf("failed to stamp updated: %w", err)
+	}
+	w.paths[wi.ID] = path
+	w.remember(path)
+	w.lint(path)
+	return nil
+}
+
+// remember records path as the watcher last saw it
+func (w *workWatcher) remember(path string) {
+	if content, err := os.ReadFile(path); err == nil {
+		w.contents[path] = string(content)
+	}
+}
+
+// lint prints the validation errors for one work item
+func (w *workWatcher) lint(path string) {
+	result, err := validation.ValidateWorkItemFiles(w.cfg, []string{path})
+	if err != nil {
+		fmt.Printf("%s: failed to validate: %v\n", path, err)
+		return
+	}
+	for _, e := range result.Errors {
+		fmt.Printf("  %s\n", e.Error())
+	}
+}
+
+// save commits the changes like kira save. Its own timestamp updates are
+// then recorded so they are not taken for edits.
+func (w *workWatcher) save() {
+	if err := saveWorkItems(w.cfg, os.Stdout, "", saveOptions{}); err != nil {
+		fmt.Printf("Auto-save failed: %v\n", err)
+	}
+	w.rescan()
+}
@@ -0,0 +1,122 @@
+//go:build linux
+
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const inotifyMask = syscall.IN_CLOSE_WRITE | syscall.IN_MOVED_TO | syscall.IN_MOVED_FROM |
+	syscall.IN_CREATE | syscall.IN_DELETE
+
+// notifyWorkChanges watches root and its subfolders with inotify and calls
+// changed with the files written, moved or deleted, in batches once events
+// settle. It returns an error straight away if inotify is unavailable and
+// nil once stop is closed.
+func notifyWorkChanges(root string, stop <-chan struct{}, changed func([]string)) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return fmt.Errorf("failed to start inotify: %w", err)
+	}
+	// A non-blocking os.File lets the runtime poller wake Read on Close
+	file := os.NewFile(uintptr(fd), "inotify")
+	defer file.Close()
+
+	dirs := make(map[int32]string)
+	watch := func(dir string) []string {
+		var files []string
+		filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return nil
+			}
+			if !info.IsDir() {
+				files = append(files, path)
+				return nil
+			}
+			if wd, err := syscall.InotifyAddWatch(fd, path, inotifyMask); err == nil {
+				dirs[int32(wd)] = path
+			}
+			return nil
+		})
+		return files
+	}
+	watch(root)
+	if len(dirs) == 0 {
+		return fmt.Errorf("failed to watch %s", root)
+	}
+
+	reads := make(chan []byte)
+	go func() {
+		for {
+			buf := make([]byte, 64*1024)
+			n, err := file.Read(buf)
+			if err != nil {
+				close(reads)
+				return
+			}
+			select {
+			case reads <- buf[:n]:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	pending := make(map[string]bool)
+	var settle <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			return nil
+		case buf, ok := <-reads:
+			if !ok {
+				return nil
+			}
+			for len(buf) >= syscall.SizeofInotifyEvent {
+				event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[0]))
+				end := syscall.SizeofInotifyEvent + int(event.Len)
+				name := string(bytes.TrimRight(buf[syscall.SizeofInotifyEvent:end], "\x00"))
+				buf = buf[end:]
+
+				switch {
+				case event.Mask&syscall.IN_Q_OVERFLOW != 0:
+					// Events were lost: report everything
+					for _, path := range watch(root) {
+						pending[path] = true
+					}
+				case event.Mask&syscall.IN_IGNORED != 0:
+					delete(dirs, event.Wd)
+				case dirs[event.Wd] == "" || name == "":
+				case event.Mask&syscall.IN_ISDIR != 0:
+					// A new or moved-in folder: watch it and report its files
+					if event.Mask&(syscall.IN_CREATE|syscall.IN_MOVED_TO) != 0 {
+						for _, path := range watch(filepath.Join(dirs[event.Wd], name)) {
+							pending[path] = true
+						}
+					}
+				case event.Mask&syscall.IN_CREATE == 0:
+					pending[filepath.Join(dirs[event.Wd], name)] = true
+				}
+			}
+			if len(pending) > 0 {
+				settle = time.After(100 * time.Millisecond)
+			}
+		case <-settle:
+			settle = nil
+			paths := make([]string, 0, len(pending))
+			for path := range pending {
+				paths = append(paths, path)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]bool)
+			changed(paths)
+		}
+	}
+}
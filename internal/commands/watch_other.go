@@ -0,0 +1,8 @@
+//go:build !linux
+
+package commands
+
+// notifyWorkChanges is only implemented on Linux; kira watch polls elsewhere
+func notifyWorkChanges(root string, stop <-chan struct{}, changed func([]string)) error {
+	return errNotifyUnsupported
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestWorkWatcher(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.Chdir(tmpDir))
+	defer os.Chdir("/")
+	require.NoError(t, initializeWorkspace("."))
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+
+	item := func(id, status string) string {
+		return "---\nid: " + id + "\ntitle: Item " + id + "\nstatus: " + status + "\nkind: task\ncreated: 2024-01-01\n---\n# Item\n"
+	}
+	require.NoError(t, os.WriteFile(".work/1_todo/001-a.task.md", []byte(item("001", "todo")), 0644))
+	require.NoError(t, os.WriteFile(".work/1_todo/002-b.task.md", []byte(item("002", "todo")), 0644))
+	w := newWorkWatcher(cfg)
+	assert.False(t, w.handle([]string{".work/1_todo/001-a.task.md"}), "unchanged files are ignored")
+
+	// status: edited by hand moves the file
+	require.NoError(t, os.WriteFile(".work/1_todo/001-a.task.md", []byte(item("001", "review")), 0644))
+	assert.True(t, w.handle([]string{".work/1_todo/001-a.task.md"}))
+	content, err := os.ReadFile(".work/3_review/001-a.task.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "status: review\n")
+	assert.Contains(t, string(content), "updated: ")
+
+	// A file moved to another folder takes its status
+	require.NoError(t, os.Rename(".work/1_todo/002-b.task.md", ".work/2_doing/002-b.task.md"))
+	assert.True(t, w.handle([]string{".work/1_todo/002-b.task.md", ".work/2_doing/002-b.task.md"}))
+	content, err = os.ReadFile(".work/2_doing/002-b.task.md")
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "status: doing\n")
+
+	// The watcher's own writes are not taken for edits
+	assert.False(t, w.handle([]string{".work/3_review/001-a.task.md", ".work/2_doing/002-b.task.md", ".work/1_todo/001-a.task.md"}))
+
+	// Templates and the archive are left alone
+	assert.Equal(t, "", watchedStatus(cfg, ".work/templates/task.md"))
+	assert.Equal(t, "", watchedStatus(cfg, ".work/z_archive/2024-01-01/001-a.task.md"))
+	assert.Equal(t, "todo", watchedStatus(cfg, ".work/1_todo/003-c.task.md"))
+}
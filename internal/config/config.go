@@ -15,7 +15,12 @@ type Config struct {
 	Validation    ValidationConfig  `yaml:"validation"`
 	Commit        CommitConfig      `yaml:"commit"`
 	Release       ReleaseConfig     `yaml:"release"`
+	Archive       ArchiveConfig     `yaml:"archive"`
+	Hooks         HooksConfig       `yaml:"hooks"`
+	Board         BoardConfig       `yaml:"board,omitempty"`
 	DefaultStatus string            `yaml:"default_status"`
+	// TemplatePacks records installed template packs by name
+	TemplatePacks map[string]TemplatePack `yaml:"template_packs,omitempty"`
 }
 
 type ValidationConfig struct {
@@ -26,11 +31,66 @@ type ValidationConfig struct {
 
 type CommitConfig struct {
 	DefaultMessage string `yaml:"default_message"`
+	// MessageTemplate builds the kira save message from the staged changes
+	// (text/template over .Type, .Scope, .Summary, .Events and .Items)
+	MessageTemplate string `yaml:"message_template,omitempty"`
 }
 
 type ReleaseConfig struct {
-	ReleasesFile      string `yaml:"releases_file"`
-	ArchiveDateFormat string `yaml:"archive_date_format"`
+	ReleasesFile      string          `yaml:"releases_file"`
+	ArchiveDateFormat string          `yaml:"archive_date_format"`
+	Format            string          `yaml:"format"`
+	GroupBy           string          `yaml:"group_by"`
+	Groups            []ReleaseGroup  `yaml:"groups,omitempty"`
+	NotesTemplate     string          `yaml:"notes_template,omitempty"`
+	Outputs           []ReleaseOutput `yaml:"outputs,omitempty"`
+}
+
+// ArchiveConfig is the retention policy for the archive folder. Batches older
+// than CompactAfterMonths are bundled into one file per quarter; 0 disables it.
+type ArchiveConfig struct {
+	CompactAfterMonths int    `yaml:"compact_after_months"`
+	BundleFormat       string `yaml:"bundle_format"`
+}
+
+// BoardConfig configures kira board. WIPLimits caps how many items a status
+// should hold; statuses without a limit are unlimited.
+type BoardConfig struct {
+	WIPLimits map[string]int `yaml:"wip_limits,omitempty"`
+}
+
+// HooksConfig drives the git hooks installed by kira git install-hooks.
+// CommitRefs is how code commits without a work item reference are treated:
+// off, warn or require. Transitions maps closing keywords such as "fixes" to
+// the status referenced items move to after the commit.
+type HooksConfig struct {
+	CommitRefs  string            `yaml:"commit_refs"`
+	Transitions map[string]string `yaml:"transitions"`
+}
+
+// TemplatePack is a template pack vendored into .work/templates/<name>
+type TemplatePack struct {
+	Source  string   `yaml:"source"`
+	Ref     string   `yaml:"ref,omitempty"`
+	Version string   `yaml:"version"`
+	Kinds   []string `yaml:"kinds"`
+}
+
+// ReleaseOutput is an extra sink for release notes: markdown, html, json or atom.
+// Path may contain {version} and {date} placeholders.
+type ReleaseOutput struct {
+	Type       string `yaml:"type"`
+	Path       string `yaml:"path"`
+	Title      string `yaml:"title,omitempty"`
+	Link       string `yaml:"link,omitempty"`
+	MaxEntries int    `yaml:"max_entries,omitempty"`
+}
+
+// ReleaseGroup is a heading in the release notes and the kinds or tags filed under it
+type ReleaseGroup struct {
+	Title string   `yaml:"title"`
+	Kinds []string `yaml:"kinds,omitempty"`
+	Tags  []string `yaml:"tags,omitempty"`
 }
 
 var DefaultConfig = Config{
@@ -56,11 +116,25 @@ var DefaultConfig = Config{
 		StatusValues:   []string{"backlog", "todo", "doing", "review", "done", "released", "abandoned", "archived"},
 	},
 	Commit: CommitConfig{
-		DefaultMessage: "Update work items",
+		DefaultMessage:  "Update work items",
+		MessageTemplate: "{{ .Type }}({{ .Scope }}): {{ .Summary }}",
 	},
 	Release: ReleaseConfig{
 		ReleasesFile:      "RELEASES.md",
 		ArchiveDateFormat: "2006-01-02",
+		Format:            "markdown",
+		GroupBy:           "kind",
+	},
+	Archive: ArchiveConfig{
+		BundleFormat: "markdown",
+	},
+	Hooks: HooksConfig{
+		CommitRefs: "warn",
+		Transitions: map[string]string{
+			"fixes":    "review",
+			"closes":   "review",
+			"resolves": "review",
+		},
 	},
 }
 
@@ -126,6 +200,9 @@ func mergeWithDefaults(config *Config) {
 	if config.Commit.DefaultMessage == "" {
 		config.Commit.DefaultMessage = DefaultConfig.Commit.DefaultMessage
 	}
+	if config.Commit.MessageTemplate == "" {
+		config.Commit.MessageTemplate = DefaultConfig.Commit.MessageTemplate
+	}
 
 	if config.Release.ReleasesFile == "" {
 		config.Release.ReleasesFile = DefaultConfig.Release.ReleasesFile
@@ -133,6 +210,23 @@ func mergeWithDefaults(config *Config) {
 	if config.Release.ArchiveDateFormat == "" {
 		config.Release.ArchiveDateFormat = DefaultConfig.Release.ArchiveDateFormat
 	}
+	if config.Release.Format == "" {
+		config.Release.Format = DefaultConfig.Release.Format
+	}
+	if config.Release.GroupBy == "" {
+		config.Release.GroupBy = DefaultConfig.Release.GroupBy
+	}
+
+	if config.Archive.BundleFormat == "" {
+		config.Archive.BundleFormat = DefaultConfig.Archive.BundleFormat
+	}
+
+	if config.Hooks.CommitRefs == "" {
+		config.Hooks.CommitRefs = DefaultConfig.Hooks.CommitRefs
+	}
+	if config.Hooks.Transitions == nil {
+		config.Hooks.Transitions = DefaultConfig.Hooks.Transitions
+	}
 
 	if config.DefaultStatus == "" {
 		config.DefaultStatus = DefaultConfig.DefaultStatus
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigPath returns the kira.yml in use: the root-level file, the legacy
+// .work/kira.yml, or the root-level path when neither exists yet
+func ConfigPath() string {
+	if _, err := os.Stat("kira.yml"); err == nil {
+		return "kira.yml"
+	}
+	legacyPath := filepath.Join(".work", "kira.yml")
+	if _, err := os.Stat(legacyPath); err == nil {
+		return legacyPath
+	}
+	return "kira.yml"
+}
+
+// SetValue sets a nested key in kira.yml, creating intermediate mappings.
+// Unlike SaveConfig it edits the file in place, so comments, key order and
+// settings left to their defaults are preserved.
+func SetValue(keys []string, value interface{}) error {
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return fmt.Errorf("failed to encode %v: %w", keys, err)
+	}
+	return editConfigFile(func(doc *yaml.Node) error {
+		node := doc
+		for i, key := range keys {
+			idx := mappingIndex(node, key)
+			if i == len(keys)-1 {
+				if idx >= 0 {
+					node.Content[idx+1] = &valueNode
+				} else {
+					node.Content = append(node.Content, scalarNode(key), &valueNode)
+				}
+				return nil
+			}
+			if idx < 0 || node.Content[idx+1].Kind != yaml.MappingNode {
+				child := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+				if idx >= 0 {
+					node.Content[idx+1] = child
+				} else {
+					node.Content = append(node.Content, scalarNode(key), child)
+				}
+				node = child
+				continue
+			}
+			node = node.Content[idx+1]
+		}
+		return nil
+	})
+}
+
+// DeleteValue removes a nested key from kira.yml if it is present
+func DeleteValue(keys []string) error {
+	return editConfigFile(func(doc *yaml.Node) error {
+		node := doc
+		for i, key := range keys {
+			idx := mappingIndex(node, key)
+			if idx < 0 {
+				return nil
+			}
+			if i == len(keys)-1 {
+				node.Content = append(node.Content[:idx], node.Content[idx+2:]...)
+				return nil
+			}
+			node = node.Content[idx+1]
+		}
+		return nil
+	})
+}
+
+func editConfigFile(edit func(doc *yaml.Node) error) error {
+	path := ConfigPath()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if root.Kind == 0 {
+		root = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	doc := root.Content[0]
+	if doc.Kind != yaml.MappingNode {
+		return fmt.Errorf("failed to edit config file: %s is not a mapping", path)
+	}
+
+	if err := edit(doc); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(4)
+	if err := enc.Encode(&root); err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	return nil
+}
+
+func mappingIndex(node *yaml.Node, key string) int {
+	if node.Kind != yaml.MappingNode {
+		return -1
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
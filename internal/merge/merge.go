@@ -0,0 +1,335 @@
+// Package merge does three-way merges of work item files. The front matter
+// is merged field by field and the body section by section, so concurrent
+// edits to different fields or sections never conflict.
+package merge
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Conflict markers, as git writes them
+const (
+	markerOurs   = "<<<<<<< ours\n"
+	markerSep    = "=======\n"
+	markerTheirs = ">>>>>>> theirs\n"
+)
+
+// ErrNoFrontMatter is returned when a side is not a work item with front
+// matter; such files need a plain line-based merge
+var ErrNoFrontMatter = fmt.Errorf("not a work item with front matter")
+
+// Result is a merged work item. Conflicts names the front matter fields and
+// body sections left with conflict markers.
+type Result struct {
+	Content   string
+	Conflicts []string
+}
+
+// part is a front matter field or a body section with its raw text
+type part struct {
+	key  string
+	text string
+}
+
+// WorkItem merges ours and theirs, two descendants of base. A field or
+// section changed on one side only takes that side's version. updated takes
+// the later of the two timestamps and list fields such as tags combine the
+// additions and removals of both sides. Anything else changed differently on
+// both sides is a conflict.
+func WorkItem(base, ours, theirs string) (Result, error) {
+	var fm, body [3][]part
+	for i, content := range []string{base, ours, theirs} {
+		front, rest, ok := splitFrontMatter(content)
+		if !ok {
+			if i == 0 && strings.TrimSpace(content) == "" {
+				continue // added on both sides: there is no base
+			}
+			return Result{}, ErrNoFrontMatter
+		}
+		fm[i] = splitFields(front)
+		body[i] = splitSections(rest)
+	}
+
+	var result Result
+	fields, conflicts := mergeParts(fm[0], fm[1], fm[2], resolveField)
+	for _, c := range conflicts {
+		result.Conflicts = append(result.Conflicts, "field "+c)
+	}
+	sections, conflicts := mergeParts(body[0], body[1], body[2], nil)
+	for _, c := range conflicts {
+		if c == "" {
+			c = "(preamble)"
+		}
+		result.Conflicts = append(result.Conflicts, "section "+strings.TrimSpace(strings.TrimLeft(stripOrdinal(c), "#")))
+	}
+
+	var b strings.Builder
+	b.WriteString("---\n")
+	for _, p := range fields {
+		b.WriteString(p.text)
+	}
+	b.WriteString("---\n")
+	for _, p := range sections {
+		b.WriteString(p.text)
+	}
+	result.Content = b.String()
+	return result, nil
+}
+
+// splitFrontMatter returns the lines between the leading --- lines and the
+// rest of the file
+func splitFrontMatter(content string) (string, string, bool) {
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "", "", false
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return strings.Join(lines[1:i], ""), strings.Join(lines[i+1:], ""), true
+		}
+	}
+	return "", "", false
+}
+
+var fieldKeyRe = regexp.MustCompile(`^([A-Za-z0-9_.-]+):`)
+
+// splitFields cuts front matter into top-level fields. Indented lines, list
+// items and comments belong to the field above them.
+func splitFields(frontMatter string) []part {
+	var parts []part
+	for _, line := range strings.SplitAfter(frontMatter, "\n") {
+		if line == "" {
+			continue
+		}
+		if m := fieldKeyRe.FindStringSubmatch(line); m != nil {
+			parts = append(parts, part{key: m[1], text: line})
+			continue
+		}
+		if len(parts) == 0 {
+			parts = append(parts, part{})
+		}
+		parts[len(parts)-1].text += line
+	}
+	return ensureNewline(parts)
+}
+
+// splitSections cuts the body at markdown headings. The text before the
+// first heading is the section with an empty key; repeated headings are
+// numbered so every key is unique.
+func splitSections(body string) []part {
+	var parts []part
+	seen := make(map[string]int)
+	for _, line := range strings.SplitAfter(body, "\n") {
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			key := strings.TrimSpace(line)
+			seen[key]++
+			if n := seen[key]; n > 1 {
+				key = fmt.Sprintf("%s\x00%d", key, n)
+			}
+			parts = append(parts, part{key: key, text: line})
+			continue
+		}
+		if len(parts) == 0 {
+			parts = append(parts, part{})
+		}
+		parts[len(parts)-1].text += line
+	}
+	return ensureNewline(parts)
+}
+
+func stripOrdinal(key string) string {
+	if i := strings.IndexByte(key, 0); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+// ensureNewline ends the last part with a newline so parts can be
+// reordered and wrapped in conflict markers
+func ensureNewline(parts []part) []part {
+	if n := len(parts); n > 0 && !strings.HasSuffix(parts[n-1].text, "\n") {
+		parts[n-1].text += "\n"
+	}
+	return parts
+}
+
+// resolver merges a key changed differently on both sides; ok is false when
+// it cannot. A nil value means the key is absent on that side.
+type resolver func(key string, base, ours, theirs *string) (text *string, ok bool)
+
+// mergeParts merges three versions of an ordered list of parts. The result
+// follows ours' order, with parts added only by theirs placed after the part
+// that precedes them there.
+func mergeParts(base, ours, theirs []part, resolve resolver) ([]part, []string) {
+	b, o, t := index(base), index(ours), index(theirs)
+	var conflicts []string
+
+	merged := func(key string) (string, bool) {
+		bv, ov, tv := b[key], o[key], t[key]
+		var text *string
+		switch {
+		case equal(ov, tv), equal(bv, tv):
+			text = ov
+		case equal(bv, ov):
+			text = tv
+		default:
+			if resolve != nil {
+				if r, ok := resolve(key, bv, ov, tv); ok {
+					text = r
+					break
+				}
+			}
+			conflicts = append(conflicts, key)
+			return markerOurs + deref(ov) + markerSep + deref(tv) + markerTheirs, true
+		}
+		if text == nil {
+			return "", false
+		}
+		return *text, true
+	}
+
+	var result []part
+	placed := make(map[string]bool)
+	for _, p := range ours {
+		placed[p.key] = true
+		if text, ok := merged(p.key); ok {
+			result = append(result, part{key: p.key, text: text})
+		}
+	}
+	for i, p := range theirs {
+		if placed[p.key] {
+			continue
+		}
+		placed[p.key] = true
+		text, ok := merged(p.key)
+		if !ok {
+			continue
+		}
+		at := 0
+		if i > 0 {
+			for j, r := range result {
+				if r.key == theirs[i-1].key {
+					at = j + 1
+				}
+			}
+		}
+		result = append(result[:at], append([]part{{key: p.key, text: text}}, result[at:]...)...)
+	}
+	return result, conflicts
+}
+
+func index(parts []part) map[string]*string {
+	m := make(map[string]*string)
+	for i := range parts {
+		m[parts[i].key] = &parts[i].text
+	}
+	return m
+}
+
+func equal(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// resolveField handles the fields that can be combined: updated keeps the
+// later timestamp and lists of scalars merge their additions and removals
+func resolveField(key string, base, ours, theirs *string) (*string, bool) {
+	if ours == nil || theirs == nil {
+		return nil, false
+	}
+	if key == "updated" {
+		if fieldValue(*theirs) > fieldValue(*ours) {
+			return theirs, true
+		}
+		return ours, true
+	}
+
+	bl, bok := listValue(key, base)
+	ol, ook := listValue(key, ours)
+	tl, tok := listValue(key, theirs)
+	if !ook || !tok || (base != nil && !bok) {
+		return nil, false
+	}
+	var items []string
+	for _, item := range ol {
+		if contains(tl, item) || !contains(bl, item) {
+			items = append(items, item)
+		}
+	}
+	for _, item := range tl {
+		if !contains(bl, item) && !contains(items, item) {
+			items = append(items, item)
+		}
+	}
+
+	seq := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, item := range items {
+		seq.Content = append(seq.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: item})
+	}
+	out, err := yaml.Marshal(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{
+		{Kind: yaml.ScalarNode, Value: key},
+		seq,
+	}})
+	if err != nil {
+		return nil, false
+	}
+	text := string(out)
+	return &text, true
+}
+
+// fieldValue is the scalar value of a single-line field
+func fieldValue(text string) string {
+	if i := strings.Index(text, ":"); i >= 0 {
+		return strings.Trim(strings.TrimSpace(text[i+1:]), `"'`)
+	}
+	return text
+}
+
+// listValue decodes a field holding a list of scalars
+func listValue(key string, text *string) ([]string, bool) {
+	if text == nil {
+		return nil, true
+	}
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(*text), &doc); err != nil {
+		return nil, false
+	}
+	seq, ok := doc[key].([]interface{})
+	if !ok {
+		return nil, false
+	}
+	var items []string
+	for _, v := range seq {
+		switch v.(type) {
+		case []interface{}, map[string]interface{}:
+			return nil, false
+		}
+		items = append(items, fmt.Sprint(v))
+	}
+	return items, true
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package merge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseItem = `---
+id: 012
+title: Login fix
+status: todo
+tags: [auth]
+created: 2025-01-01
+updated: 2025-01-02T10:00:00Z
+---
+# Login fix
+
+## Context
+Users cannot log in.
+
+## Notes
+None yet.
+`
+
+func TestWorkItemMergesFieldsAndSections(t *testing.T) {
+	ours := `---
+id: 012
+title: Login fix
+status: doing
+tags: [auth, urgent]
+created: 2025-01-01
+updated: 2025-01-03T09:00:00Z
+---
+# Login fix
+
+## Context
+Users cannot log in with SSO.
+
+## Notes
+None yet.
+`
+	theirs := `---
+id: 012
+title: Login fix
+status: todo
+tags: [security]
+assignee: sam
+created: 2025-01-01
+updated: 2025-01-04T08:00:00Z
+---
+# Login fix
+
+## Context
+Users cannot log in.
+
+## Notes
+Reproduced on staging.
+
+## Plan
+Patch the callback.
+`
+	result, err := WorkItem(baseItem, ours, theirs)
+	require.NoError(t, err)
+	assert.Empty(t, result.Conflicts)
+	assert.Equal(t, `---
+id: 012
+title: Login fix
+status: doing
+tags: [urgent, security]
+assignee: sam
+created: 2025-01-01
+updated: 2025-01-04T08:00:00Z
+---
+# Login fix
+
+## Context
+Users cannot log in with SSO.
+
+## Notes
+Reproduced on staging.
+
+## Plan
+Patch the callback.
+`, result.Content)
+}
+
+func TestWorkItemConflicts(t *testing.T) {
+	ours := `---
+id: 012
+title: Login fix
+status: doing
+tags: [auth]
+created: 2025-01-01
+updated: 2025-01-02T10:00:00Z
+---
+# Login fix
+
+## Context
+Users cannot log in.
+
+## Notes
+Ours.
+`
+	theirs := `---
+id: 012
+title: Login fix
+status: review
+tags: [auth]
+created: 2025-01-01
+updated: 2025-01-02T10:00:00Z
+---
+# Login fix
+
+## Context
+Users cannot log in.
+
+## Notes
+Theirs.
+`
+	result, err := WorkItem(baseItem, ours, theirs)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"field status", "section Notes"}, result.Conflicts)
+	assert.Contains(t, result.Content, "<<<<<<< ours\nstatus: doing\n=======\nstatus: review\n>>>>>>> theirs\n")
+	assert.Contains(t, result.Content, "<<<<<<< ours\n## Notes\nOurs.\n=======\n## Notes\nTheirs.\n>>>>>>> theirs\n")
+	assert.Contains(t, result.Content, "## Context\nUsers cannot log in.\n")
+}
+
+func TestWorkItemWithoutFrontMatter(t *testing.T) {
+	_, err := WorkItem("# Ideas\n", "# Ideas\n- a\n", "# Ideas\n- b\n")
+	assert.ErrorIs(t, err, ErrNoFrontMatter)
+}
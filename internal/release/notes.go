@@ -0,0 +1,402 @@
+package release
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"kira/internal/config"
+	"kira/internal/validation"
+)
+
+const (
+	FormatMarkdown       = "markdown"
+	FormatKeepAChangelog = "keepachangelog"
+
+	GroupByKind = "kind"
+	GroupByTag  = "tag"
+
+	// SectionTitle is the body heading that holds an item's public-facing notes
+	SectionTitle = "Release Notes"
+
+	otherGroup = "Other"
+)
+
+// Entry is a single released work item as it appears in the notes
+type Entry struct {
+	ID       string
+	Title    string
+	Kind     string
+	Assigned string
+	Tags     []string
+	Notes    string
+	Path     string
+	Metadata map[string]interface{}
+	// Commits are the code commits linked to the item by a reference in
+	// their message or the name of their branch, newest first
+	Commits []Commit
+}
+
+// Commit is a commit linked to a released item
+type Commit struct {
+	Hash    string
+	Author  string
+	Date    string
+	Subject string
+}
+
+// Group is a titled list of entries, e.g. "Features" or "Fixed"
+type Group struct {
+	Title   string
+	Entries []Entry
+}
+
+// Notes is the data handed to the release notes template. Items holds every
+// released entry, including those without notes.
+type Notes struct {
+	Version string
+	Date    string
+	Groups  []Group
+	Items   []Entry
+}
+
+// Empty reports whether no group has any entries
+func (n Notes) Empty() bool {
+	for _, g := range n.Groups {
+		if len(g.Entries) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Entries returns every entry across all groups in display order
+func (n Notes) Entries() []Entry {
+	var entries []Entry
+	for _, g := range n.Groups {
+		entries = append(entries, g.Entries...)
+	}
+	return entries
+}
+
+const markdownTemplate = `# Release {{if .Version}}{{.Version}} - {{end}}{{.Date}}
+{{- range .Groups}}
+
+## {{.Title}}
+{{range .Entries}}
+- **{{.ID}}** {{.Title}}{{if .Assigned}} ({{.Assigned}}){{end}}
+{{- if .Notes}}
+{{indent 2 .Notes}}
+{{- end}}
+{{- end}}
+{{- end}}
+`
+
+const keepAChangelogTemplate = `## [{{if .Version}}{{.Version}}{{else}}Unreleased{{end}}] - {{.Date}}
+{{- range .Groups}}
+
+### {{.Title}}
+{{range .Entries}}
+- {{.Title}} ({{.ID}}){{if .Assigned}} - {{.Assigned}}{{end}}
+{{- if .Notes}}
+{{indent 2 .Notes}}
+{{- end}}
+{{- end}}
+{{- end}}
+`
+
+const keepAChangelogHeader = `# Changelog
+
+All notable changes to this project will be documented in this file.
+
+The format is based on [Keep a Changelog](https://keepachangelog.com/en/1.1.0/).
+
+`
+
+// DefaultGroups returns the kind-based groups used when none are configured
+func DefaultGroups(format string) []config.ReleaseGroup {
+	if format == FormatKeepAChangelog {
+		return []config.ReleaseGroup{
+			{Title: "Added", Kinds: []string{"prd"}},
+			{Title: "Fixed", Kinds: []string{"issue"}},
+			{Title: "Changed", Kinds: []string{"task", "spike"}},
+		}
+	}
+	return []config.ReleaseGroup{
+		{Title: "Features", Kinds: []string{"prd"}},
+		{Title: "Fixes", Kinds: []string{"issue"}},
+		{Title: "Tasks", Kinds: []string{"task"}},
+		{Title: "Research", Kinds: []string{"spike"}},
+	}
+}
+
+// DefaultTemplate returns the built-in notes template for a format
+func DefaultTemplate(format string) string {
+	if format == FormatKeepAChangelog {
+		return keepAChangelogTemplate
+	}
+	return markdownTemplate
+}
+
+// LoadEntry reads a work item file and extracts its release notes section.
+// Empty or placeholder sections leave Notes blank.
+func LoadEntry(path string) (Entry, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	item, err := validation.ParseWorkItemFile(path)
+	if err != nil {
+		return Entry{}, err
+	}
+
+	entry := Entry{
+		ID:       item.ID,
+		Title:    item.Title,
+		Kind:     item.Kind,
+		Tags:     stringList(item.Fields["tags"]),
+		Path:     path,
+		Metadata: map[string]interface{}{"id": item.ID, "title": item.Title, "status": item.Status, "kind": item.Kind, "created": item.Created},
+	}
+	for k, v := range item.Fields {
+		entry.Metadata[k] = v
+	}
+	if assigned, ok := item.Fields["assigned"].(string); ok {
+		entry.Assigned = assigned
+	}
+
+	notes := ExtractSection(string(content), SectionTitle)
+	if !IsPlaceholder(notes) {
+		entry.Notes = notes
+	}
+
+	return entry, nil
+}
+
+var headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+
+// ExtractSection returns the trimmed body of the first markdown heading whose
+// text equals title. Headings inside fenced code blocks are ignored, and the
+// section ends at the next heading of the same or a higher level.
+func ExtractSection(content, title string) string {
+	lines := strings.Split(content, "\n")
+	var section []string
+	level := 0
+	inFence := false
+	inFrontMatter := len(lines) > 0 && strings.TrimSpace(lines[0]) == "---"
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if inFrontMatter {
+			if i > 0 && trimmed == "---" {
+				inFrontMatter = false
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+		}
+		if !inFence {
+			if m := headingRe.FindStringSubmatch(line); m != nil {
+				if level > 0 && len(m[1]) <= level {
+					break
+				}
+				if level == 0 && strings.EqualFold(m[2], title) {
+					level = len(m[1])
+					continue
+				}
+			}
+		}
+		if level > 0 {
+			section = append(section, line)
+		}
+	}
+
+	return strings.TrimSpace(strings.Join(section, "\n"))
+}
+
+var (
+	htmlCommentRe = regexp.MustCompile(`(?s)<!--.*?-->`)
+	placeholders  = map[string]bool{
+		"-": true, "tbd": true, "todo": true, "n/a": true, "na": true, "none": true, "(optional)": true,
+	}
+)
+
+// IsPlaceholder reports whether a section holds no real content: it is empty,
+// only HTML comments (such as unfilled template inputs), or a stock filler word.
+func IsPlaceholder(text string) bool {
+	text = strings.TrimSpace(htmlCommentRe.ReplaceAllString(text, ""))
+	if text == "" {
+		return true
+	}
+	return placeholders[strings.ToLower(strings.TrimSuffix(text, "."))]
+}
+
+// Build groups entries according to the release config. Entries without
+// notes are dropped and empty groups are omitted.
+func Build(cfg config.ReleaseConfig, entries []Entry, version string, date time.Time) Notes {
+	notes := Notes{Version: version, Date: date.Format("2006-01-02")}
+
+	groups := cfg.Groups
+	if len(groups) == 0 && cfg.GroupBy != GroupByTag {
+		groups = DefaultGroups(cfg.Format)
+	}
+
+	byTitle := make(map[string]*Group)
+	var order []string
+	add := func(title string, entry Entry) {
+		g, ok := byTitle[title]
+		if !ok {
+			g = &Group{Title: title}
+			byTitle[title] = g
+			order = append(order, title)
+		}
+		g.Entries = append(g.Entries, entry)
+	}
+	// Configured groups keep their declared order even if filled out of order
+	for _, g := range groups {
+		byTitle[g.Title] = &Group{Title: g.Title}
+		order = append(order, g.Title)
+	}
+
+	sorted := make([]Entry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ID < sorted[j].ID })
+	notes.Items = sorted
+
+	for _, entry := range sorted {
+		if entry.Notes == "" {
+			continue
+		}
+		add(groupFor(cfg.GroupBy, groups, entry), entry)
+	}
+
+	if cfg.GroupBy == GroupByTag && len(cfg.Groups) == 0 {
+		// Derived tag groups are listed alphabetically with "Other" last
+		sort.SliceStable(order, func(i, j int) bool {
+			if order[i] == otherGroup || order[j] == otherGroup {
+				return order[j] == otherGroup && order[i] != otherGroup
+			}
+			return order[i] < order[j]
+		})
+	}
+
+	for _, title := range order {
+		if g := byTitle[title]; len(g.Entries) > 0 {
+			notes.Groups = append(notes.Groups, *g)
+		}
+	}
+
+	return notes
+}
+
+func groupFor(groupBy string, groups []config.ReleaseGroup, entry Entry) string {
+	for _, g := range groups {
+		if groupBy == GroupByTag {
+			for _, tag := range entry.Tags {
+				if contains(g.Tags, tag) {
+					return g.Title
+				}
+			}
+			continue
+		}
+		if contains(g.Kinds, entry.Kind) {
+			return g.Title
+		}
+	}
+	if groupBy == GroupByTag && len(groups) == 0 && len(entry.Tags) > 0 {
+		return entry.Tags[0]
+	}
+	return otherGroup
+}
+
+// Render executes the notes template. An empty tmplText selects the built-in
+// template for the configured format.
+func Render(notes Notes, format, tmplText string) (string, error) {
+	if notes.Empty() {
+		return "", nil
+	}
+	if tmplText == "" {
+		tmplText = DefaultTemplate(format)
+	}
+
+	tmpl, err := template.New("release-notes").Funcs(template.FuncMap{
+		"indent": indent,
+		"join":   strings.Join,
+	}).Parse(tmplText)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse release notes template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, notes); err != nil {
+		return "", fmt.Errorf("failed to render release notes: %w", err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Prepend inserts rendered notes into an existing releases file. Keep a
+// Changelog files keep their header and get the new version above the
+// previous ones.
+func Prepend(existing, rendered, format string) string {
+	if format != FormatKeepAChangelog {
+		return rendered + "\n\n" + existing
+	}
+
+	if strings.TrimSpace(existing) == "" {
+		return keepAChangelogHeader + rendered + "\n"
+	}
+	if idx := strings.Index(existing, "\n## ["); idx >= 0 {
+		return existing[:idx+1] + rendered + "\n\n" + existing[idx+1:]
+	}
+	return strings.TrimRight(existing, "\n") + "\n\n" + rendered + "\n"
+}
+
+func indent(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func stringList(v interface{}) []string {
+	switch val := v.(type) {
+	case []interface{}:
+		var out []string
+		for _, item := range val {
+			if s := strings.TrimSpace(fmt.Sprint(item)); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	case string:
+		var out []string
+		for _, item := range strings.Split(strings.Trim(val, "[]"), ",") {
+			if s := strings.TrimSpace(item); s != "" {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
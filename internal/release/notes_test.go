@@ -0,0 +1,132 @@
+package release
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestExtractSection(t *testing.T) {
+	t.Run("extracts section up to next heading of same level", func(t *testing.T) {
+		content := `---
+id: 001
+---
+# Feature
+
+## Release Notes
+Users can now log in.
+
+### Details
+Uses OAuth.
+
+## Other
+ignored
+`
+		section := ExtractSection(content, "Release Notes")
+		assert.Equal(t, "Users can now log in.\n\n### Details\nUses OAuth.", section)
+	})
+
+	t.Run("ignores headings inside code fences and similar headings", func(t *testing.T) {
+		content := "# Feature\n\n## Release Notes for reviewers\nnope\n\n```\n## Release Notes\n```\n\n## Release Notes\nReal notes\n"
+		assert.Equal(t, "Real notes", ExtractSection(content, "Release Notes"))
+	})
+
+	t.Run("returns empty when section missing", func(t *testing.T) {
+		assert.Equal(t, "", ExtractSection("# Feature\n\nbody\n", "Release Notes"))
+	})
+}
+
+func TestIsPlaceholder(t *testing.T) {
+	assert.True(t, IsPlaceholder(""))
+	assert.True(t, IsPlaceholder(`<!--input-string:release_notes:"Public-facing changes (optional)"-->`))
+	assert.True(t, IsPlaceholder("TBD"))
+	assert.False(t, IsPlaceholder("Adds dark mode"))
+}
+
+func TestBuildAndRender(t *testing.T) {
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{ID: "002", Title: "Login fails", Kind: "issue", Tags: []string{"security"}, Notes: "Fixed login on Safari."},
+		{ID: "001", Title: "Dark mode", Kind: "prd", Assigned: "ana@example.com", Tags: []string{"ui"}, Notes: "Adds dark mode."},
+		{ID: "003", Title: "Refactor", Kind: "task"},
+	}
+
+	t.Run("groups by kind with default markdown template", func(t *testing.T) {
+		cfg := config.ReleaseConfig{Format: FormatMarkdown, GroupBy: GroupByKind}
+		notes := Build(cfg, entries, "v1.2.0", date)
+		require.Len(t, notes.Groups, 2)
+		assert.Equal(t, "Features", notes.Groups[0].Title)
+		assert.Equal(t, "Fixes", notes.Groups[1].Title)
+
+		out, err := Render(notes, cfg.Format, "")
+		require.NoError(t, err)
+		assert.Contains(t, out, "# Release v1.2.0 - 2025-03-01")
+		assert.Contains(t, out, "## Features")
+		assert.Contains(t, out, "- **001** Dark mode (ana@example.com)\n  Adds dark mode.")
+		assert.NotContains(t, out, "Refactor")
+	})
+
+	t.Run("groups by tag", func(t *testing.T) {
+		cfg := config.ReleaseConfig{Format: FormatMarkdown, GroupBy: GroupByTag}
+		notes := Build(cfg, entries, "", date)
+		require.Len(t, notes.Groups, 2)
+		assert.Equal(t, "security", notes.Groups[0].Title)
+		assert.Equal(t, "ui", notes.Groups[1].Title)
+	})
+
+	t.Run("renders keep a changelog", func(t *testing.T) {
+		cfg := config.ReleaseConfig{Format: FormatKeepAChangelog, GroupBy: GroupByKind}
+		out, err := Render(Build(cfg, entries, "1.2.0", date), cfg.Format, "")
+		require.NoError(t, err)
+		assert.Contains(t, out, "## [1.2.0] - 2025-03-01")
+		assert.Contains(t, out, "### Added")
+		assert.Contains(t, out, "### Fixed")
+
+		file := Prepend("", out, cfg.Format)
+		assert.Contains(t, file, "# Changelog")
+
+		older := "## [1.1.0] - 2025-01-01\n\n### Added\n\n- Old\n"
+		file = Prepend(keepAChangelogHeader+older, out, cfg.Format)
+		assert.Less(t, strings.Index(file, "[1.2.0]"), strings.Index(file, "[1.1.0]"))
+	})
+
+	t.Run("renders custom template", func(t *testing.T) {
+		cfg := config.ReleaseConfig{Format: FormatMarkdown, GroupBy: GroupByKind}
+		out, err := Render(Build(cfg, entries, "v2", date), cfg.Format, "{{range .Entries}}{{.ID}};{{end}}")
+		require.NoError(t, err)
+		assert.Equal(t, "001;002;", out)
+	})
+}
+
+func TestLoadEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "001-feature.prd.md")
+	content := `---
+id: 001
+title: Feature
+status: done
+kind: prd
+assigned: dev@example.com
+tags: [ui, api]
+created: 2024-01-01
+---
+
+# Feature
+
+## Release Notes
+<!--input-string:release_notes:"Public-facing changes (optional)"-->
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+
+	entry, err := LoadEntry(path)
+	require.NoError(t, err)
+	assert.Equal(t, "001", entry.ID)
+	assert.Equal(t, "dev@example.com", entry.Assigned)
+	assert.Equal(t, []string{"ui", "api"}, entry.Tags)
+	assert.Empty(t, entry.Notes)
+}
@@ -0,0 +1,357 @@
+package release
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"kira/internal/config"
+)
+
+const (
+	OutputMarkdown = "markdown"
+	OutputHTML     = "html"
+	OutputJSON     = "json"
+	OutputAtom     = "atom"
+
+	defaultFeedEntries = 50
+)
+
+// WriteOutputs renders notes to every configured sink and returns the paths
+// written. markdown is the already rendered release notes text; when it is
+// empty the markdown and HTML outputs are skipped, as for the releases file,
+// while the JSON document and Atom feed still record the release.
+func WriteOutputs(outputs []config.ReleaseOutput, notes Notes, markdown string) ([]string, error) {
+	var written []string
+	empty := strings.TrimSpace(markdown) == ""
+	for _, out := range outputs {
+		if out.Path == "" {
+			return written, fmt.Errorf("release output of type %q has no path", out.Type)
+		}
+		if empty && (out.Type == OutputMarkdown || out.Type == OutputHTML) {
+			continue
+		}
+		path := OutputPath(out.Path, notes)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, fmt.Errorf("failed to create directory for %s: %w", path, err)
+		}
+
+		var err error
+		switch out.Type {
+		case OutputMarkdown:
+			err = os.WriteFile(path, []byte(markdown+"\n"), 0644)
+		case OutputHTML:
+			err = writeHTML(path, out, notes)
+		case OutputJSON:
+			err = writeJSON(path, notes)
+		case OutputAtom:
+			err = writeAtom(path, out, notes)
+		default:
+			err = fmt.Errorf("unknown release output type: %s", out.Type)
+		}
+		if err != nil {
+			return written, fmt.Errorf("failed to write %s output %s: %w", out.Type, path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// OutputPath substitutes {version} and {date} in an output path. The date
+// stands in for the version when none was given.
+func OutputPath(path string, notes Notes) string {
+	version := notes.Version
+	if version == "" {
+		version = notes.Date
+	}
+	return strings.NewReplacer("{version}", version, "{date}", notes.Date).Replace(path)
+}
+
+func releaseTitle(out config.ReleaseOutput, notes Notes) string {
+	title := "Release " + notes.Date
+	if notes.Version != "" {
+		title = "Release " + notes.Version
+	}
+	if out.Title != "" {
+		title = out.Title + " - " + title
+	}
+	return title
+}
+
+var htmlPage = template.Must(template.New("page").Funcs(template.FuncMap{"markdown": markdownHTML}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; max-width: 48rem; margin: 2rem auto; padding: 0 1rem; line-height: 1.5; }
+.meta { color: #666; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">Released {{.Notes.Date}}</p>
+{{- range .Notes.Groups}}
+<h2>{{.Title}}</h2>
+<ul>
+{{- range .Entries}}
+<li id="item-{{.ID}}"><strong>{{.Title}}</strong> <span class="meta">#{{.ID}}{{if .Assigned}} &middot; {{.Assigned}}{{end}}</span>
+{{markdown .Notes}}</li>
+{{- end}}
+</ul>
+{{- end}}
+</body>
+</html>
+`))
+
+var htmlFragment = template.Must(template.New("fragment").Funcs(template.FuncMap{"markdown": markdownHTML}).Parse(`
+{{- range .Groups}}<h2>{{.Title}}</h2><ul>
+{{- range .Entries}}<li><strong>{{.Title}}</strong> (#{{.ID}}){{markdown .Notes}}</li>{{end}}</ul>
+{{- end}}`))
+
+func writeHTML(path string, out config.ReleaseOutput, notes Notes) error {
+	var buf bytes.Buffer
+	data := struct {
+		Title string
+		Notes Notes
+	}{releaseTitle(out, notes), notes}
+	if err := htmlPage.Execute(&buf, data); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// markdownHTML converts the small subset of markdown used in release notes
+// (paragraphs, bullet lists and headings) into escaped HTML.
+func markdownHTML(text string) template.HTML {
+	var b strings.Builder
+	var para []string
+	inList := false
+
+	flushPara := func() {
+		if len(para) > 0 {
+			b.WriteString("<p>" + template.HTMLEscapeString(strings.Join(para, " ")) + "</p>")
+			para = nil
+		}
+	}
+	closeList := func() {
+		if inList {
+			b.WriteString("</ul>")
+			inList = false
+		}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flushPara()
+			closeList()
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flushPara()
+			if !inList {
+				b.WriteString("<ul>")
+				inList = true
+			}
+			b.WriteString("<li>" + template.HTMLEscapeString(strings.TrimSpace(trimmed[2:])) + "</li>")
+		case headingRe.MatchString(trimmed):
+			flushPara()
+			closeList()
+			m := headingRe.FindStringSubmatch(trimmed)
+			b.WriteString("<h4>" + template.HTMLEscapeString(m[2]) + "</h4>")
+		default:
+			closeList()
+			para = append(para, trimmed)
+		}
+	}
+	flushPara()
+	closeList()
+
+	return template.HTML(b.String())
+}
+
+type jsonItem struct {
+	ID       string                 `json:"id"`
+	Title    string                 `json:"title"`
+	Kind     string                 `json:"kind"`
+	Assigned string                 `json:"assigned,omitempty"`
+	Tags     []string               `json:"tags,omitempty"`
+	Notes    string                 `json:"notes,omitempty"`
+	Group    string                 `json:"group,omitempty"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Commits  []jsonCommit           `json:"commits,omitempty"`
+}
+
+type jsonCommit struct {
+	Hash    string `json:"hash"`
+	Author  string `json:"author"`
+	Date    string `json:"date"`
+	Subject string `json:"subject"`
+}
+
+type jsonRelease struct {
+	Version string     `json:"version,omitempty"`
+	Date    string     `json:"date"`
+	Groups  []string   `json:"groups"`
+	Items   []jsonItem `json:"items"`
+}
+
+func writeJSON(path string, notes Notes) error {
+	data, err := MarshalJSON(notes)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// MarshalJSON encodes notes as a release document listing every item with
+// its full front matter, including items that had no release notes.
+func MarshalJSON(notes Notes) ([]byte, error) {
+	groupOf := make(map[string]string)
+	doc := jsonRelease{Version: notes.Version, Date: notes.Date, Groups: []string{}, Items: []jsonItem{}}
+	for _, g := range notes.Groups {
+		doc.Groups = append(doc.Groups, g.Title)
+		for _, e := range g.Entries {
+			groupOf[e.Path+"\x00"+e.ID] = g.Title
+		}
+	}
+	for _, e := range notes.Items {
+		item := jsonItem{
+			ID:       e.ID,
+			Title:    e.Title,
+			Kind:     e.Kind,
+			Assigned: e.Assigned,
+			Tags:     e.Tags,
+			Notes:    e.Notes,
+			Group:    groupOf[e.Path+"\x00"+e.ID],
+			Metadata: e.Metadata,
+		}
+		for _, c := range e.Commits {
+			item.Commits = append(item.Commits, jsonCommit(c))
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Content atomContent `xml:"content"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    []atomLink  `xml:"link,omitempty"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+// writeAtom adds the release as the newest entry of an Atom feed, replacing
+// any earlier entry for the same version and keeping at most max_entries.
+func writeAtom(path string, out config.ReleaseOutput, notes Notes) error {
+	feed := atomFeed{Title: out.Title}
+	if out.Link != "" {
+		feed.ID = out.Link
+		feed.Link = []atomLink{{Href: out.Link}}
+	}
+	if feed.Title == "" {
+		feed.Title = "Releases"
+	}
+
+	if existing, err := os.ReadFile(path); err == nil {
+		if err := xml.Unmarshal(existing, &feed); err != nil {
+			return fmt.Errorf("failed to parse existing feed: %w", err)
+		}
+		if out.Title != "" {
+			feed.Title = out.Title
+		}
+	}
+	if feed.ID == "" {
+		// A new feed without a link gets an ID of its own, kept in the file
+		id, err := newUUID()
+		if err != nil {
+			return err
+		}
+		feed.ID = "urn:uuid:" + id
+	}
+
+	var content bytes.Buffer
+	if err := htmlFragment.Execute(&content, notes); err != nil {
+		return err
+	}
+
+	key := notes.Version
+	if key == "" {
+		key = notes.Date
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	entry := atomEntry{
+		Title:   releaseTitle(config.ReleaseOutput{}, notes),
+		ID:      strings.TrimRight(feed.ID, "/") + "#" + key,
+		Updated: now,
+		Content: atomContent{Type: "html", Body: content.String()},
+	}
+	if out.Link != "" {
+		entry.Link = &atomLink{Href: strings.TrimRight(out.Link, "/") + "#" + key}
+	}
+
+	entries := []atomEntry{entry}
+	for _, e := range feed.Entries {
+		if e.ID != entry.ID {
+			entries = append(entries, e)
+		}
+	}
+	limit := out.MaxEntries
+	if limit <= 0 {
+		limit = defaultFeedEntries
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	feed.Entries = entries
+	feed.Updated = now
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append([]byte(xml.Header), append(data, '\n')...), 0644)
+}
+
+// newUUID returns a random (version 4) UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("failed to generate feed ID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
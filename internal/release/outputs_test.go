@@ -0,0 +1,109 @@
+package release
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"kira/internal/config"
+)
+
+func TestWriteOutputs(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.ReleaseConfig{Format: FormatMarkdown, GroupBy: GroupByKind}
+	entries := []Entry{
+		{ID: "001", Title: "Dark <mode>", Kind: "prd", Notes: "Adds dark mode.\n\n- toggle in settings", Metadata: map[string]interface{}{"estimate": 3}},
+		{ID: "002", Title: "Cleanup", Kind: "task"},
+	}
+	notes := Build(cfg, entries, "v1.0.0", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+	markdown, err := Render(notes, cfg.Format, "")
+	require.NoError(t, err)
+
+	outputs := []config.ReleaseOutput{
+		{Type: OutputMarkdown, Path: filepath.Join(dir, "releases", "{version}.md")},
+		{Type: OutputHTML, Path: filepath.Join(dir, "site", "{version}.html"), Title: "Acme"},
+		{Type: OutputJSON, Path: filepath.Join(dir, "{version}.json")},
+		{Type: OutputAtom, Path: filepath.Join(dir, "feed.xml"), Title: "Acme releases", Link: "https://acme.test/releases", MaxEntries: 2},
+	}
+
+	written, err := WriteOutputs(outputs, notes, markdown)
+	require.NoError(t, err)
+	assert.Len(t, written, 4)
+
+	md, err := os.ReadFile(filepath.Join(dir, "releases", "v1.0.0.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(md), "# Release v1.0.0")
+
+	page, err := os.ReadFile(filepath.Join(dir, "site", "v1.0.0.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<title>Acme - Release v1.0.0</title>")
+	assert.Contains(t, string(page), "Dark &lt;mode&gt;")
+	assert.Contains(t, string(page), "<li>toggle in settings</li>")
+
+	var doc map[string]interface{}
+	data, err := os.ReadFile(filepath.Join(dir, "v1.0.0.json"))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &doc))
+	items := doc["items"].([]interface{})
+	assert.Len(t, items, 2, "json lists every item, with or without notes")
+	assert.Equal(t, "Features", items[0].(map[string]interface{})["group"])
+
+	t.Run("atom feed is updated incrementally", func(t *testing.T) {
+		for _, version := range []string{"v1.1.0", "v1.2.0", "v1.2.0"} {
+			next := notes
+			next.Version = version
+			_, err := WriteOutputs(outputs[3:], next, markdown)
+			require.NoError(t, err)
+		}
+
+		feed, err := os.ReadFile(filepath.Join(dir, "feed.xml"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(string(feed), "<entry>"), "max_entries caps the feed and versions are not duplicated")
+		assert.Less(t, strings.Index(string(feed), "<id>https://acme.test/releases#v1.2.0</id>"), strings.Index(string(feed), "<id>https://acme.test/releases#v1.1.0</id>"))
+		assert.NotContains(t, string(feed), "releases#v1.0.0</id>")
+	})
+
+	t.Run("atom feeds without a link get their own ID", func(t *testing.T) {
+		atom := []config.ReleaseOutput{{Type: OutputAtom, Path: filepath.Join(dir, "local.xml")}}
+		for i := 0; i < 2; i++ {
+			_, err := WriteOutputs(atom, notes, markdown)
+			require.NoError(t, err)
+		}
+		feed, err := os.ReadFile(filepath.Join(dir, "local.xml"))
+		require.NoError(t, err)
+		match := regexp.MustCompile(`<id>(urn:uuid:[0-9a-f-]{36})</id>`).FindSubmatch(feed)
+		require.NotNil(t, match, string(feed))
+		assert.Contains(t, string(feed), "<id>"+string(match[1])+"#v1.0.0</id>")
+		assert.Equal(t, 1, strings.Count(string(feed), "<entry>"))
+	})
+
+	t.Run("items without notes still get json and atom", func(t *testing.T) {
+		silent := Build(cfg, []Entry{{ID: "003", Title: "Refactor", Kind: "task"}}, "v2.0.0", time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC))
+		markdown, err := Render(silent, cfg.Format, "")
+		require.NoError(t, err)
+		written, err := WriteOutputs(outputs, silent, markdown)
+		require.NoError(t, err)
+		assert.Equal(t, []string{filepath.Join(dir, "v2.0.0.json"), filepath.Join(dir, "feed.xml")}, written)
+		assert.NoFileExists(t, filepath.Join(dir, "releases", "v2.0.0.md"))
+		assert.NoFileExists(t, filepath.Join(dir, "site", "v2.0.0.html"))
+
+		data, err := os.ReadFile(filepath.Join(dir, "v2.0.0.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"Refactor"`)
+		feed, err := os.ReadFile(filepath.Join(dir, "feed.xml"))
+		require.NoError(t, err)
+		assert.Contains(t, string(feed), "<id>https://acme.test/releases#v2.0.0</id>")
+	})
+
+	t.Run("rejects unknown output types", func(t *testing.T) {
+		_, err := WriteOutputs([]config.ReleaseOutput{{Type: "pdf", Path: filepath.Join(dir, "x.pdf")}}, notes, markdown)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "unknown release output type")
+	})
+}
@@ -0,0 +1,116 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultDateFormat is the date format of created, due and release dates
+const DefaultDateFormat = "2006-01-02"
+
+// dateTokens maps the human tokens used in templates to Go layout elements.
+// Lower-case mm is the month and upper-case MM the minute.
+var dateTokens = strings.NewReplacer(
+	"yyyy", "2006",
+	"yy", "06",
+	"mm", "01",
+	"dd", "02",
+	"HH", "15",
+	"MM", "04",
+	"SS", "05",
+)
+
+// DateLayout turns a format such as yyyy-mm-dd into a Go time layout. Formats
+// that are already Go layouts pass through unchanged.
+func DateLayout(format string) string {
+	if format == "" {
+		return DefaultDateFormat
+	}
+	return dateTokens.Replace(format)
+}
+
+var relativeDateRe = regexp.MustCompile(`^([+-]\d+)\s*([dwmy])$`)
+
+// ResolveDate accepts a date in the given format or a relative expression
+// and returns it formatted. Relative expressions are today, tomorrow,
+// yesterday, +3d, -1w, +2m, +1y, eow (this Friday), eom, a weekday name
+// (the next one) or "next <weekday>".
+func ResolveDate(value, format string, now time.Time) (string, error) {
+	layout := DateLayout(format)
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+	if _, err := time.Parse(layout, value); err == nil {
+		return value, nil
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	expr := strings.ToLower(value)
+
+	var date time.Time
+	switch {
+	case expr == "today":
+		date = today
+	case expr == "tomorrow":
+		date = today.AddDate(0, 0, 1)
+	case expr == "yesterday":
+		date = today.AddDate(0, 0, -1)
+	case expr == "eow":
+		date = nextWeekday(today, time.Friday, true)
+	case expr == "eom":
+		date = time.Date(today.Year(), today.Month()+1, 0, 0, 0, 0, 0, today.Location())
+	case relativeDateRe.MatchString(expr):
+		m := relativeDateRe.FindStringSubmatch(expr)
+		n, _ := strconv.Atoi(m[1])
+		switch m[2] {
+		case "d":
+			date = today.AddDate(0, 0, n)
+		case "w":
+			date = today.AddDate(0, 0, 7*n)
+		case "m":
+			date = today.AddDate(0, n, 0)
+		case "y":
+			date = today.AddDate(n, 0, 0)
+		}
+	default:
+		name := strings.TrimPrefix(expr, "next ")
+		day, ok := weekdays[name]
+		if !ok {
+			return "", fmt.Errorf("invalid date %q: expected format %s or a relative date such as +3d, next friday or eow", value, format)
+		}
+		date = nextWeekday(today, day, false)
+	}
+
+	return date.Format(layout), nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"sunday":    time.Sunday,
+	"monday":    time.Monday,
+	"tuesday":   time.Tuesday,
+	"wednesday": time.Wednesday,
+	"thursday":  time.Thursday,
+	"friday":    time.Friday,
+	"saturday":  time.Saturday,
+	"sun":       time.Sunday,
+	"mon":       time.Monday,
+	"tue":       time.Tuesday,
+	"wed":       time.Wednesday,
+	"thu":       time.Thursday,
+	"fri":       time.Friday,
+	"sat":       time.Saturday,
+}
+
+// nextWeekday returns the next given weekday after from, or from itself when
+// it already is that day and includeToday is set
+func nextWeekday(from time.Time, day time.Weekday, includeToday bool) time.Time {
+	diff := (int(day) - int(from.Weekday()) + 7) % 7
+	if diff == 0 && !includeToday {
+		diff = 7
+	}
+	return from.AddDate(0, 0, diff)
+}
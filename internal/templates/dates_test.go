@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateLayout(t *testing.T) {
+	assert.Equal(t, "2006-01-02", DateLayout("yyyy-mm-dd"))
+	assert.Equal(t, "02/01/06 15:04", DateLayout("dd/mm/yy HH:MM"))
+	assert.Equal(t, "2006-01-02", DateLayout("2006-01-02"))
+}
+
+func TestResolveDate(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 3, 12, 15, 30, 0, 0, time.UTC)
+
+	tests := map[string]string{
+		"2025-04-01":  "2025-04-01",
+		"today":       "2025-03-12",
+		"tomorrow":    "2025-03-13",
+		"+3d":         "2025-03-15",
+		"-1w":         "2025-03-05",
+		"+1m":         "2025-04-12",
+		"eow":         "2025-03-14",
+		"eom":         "2025-03-31",
+		"friday":      "2025-03-14",
+		"next friday": "2025-03-14",
+		"Wednesday":   "2025-03-19",
+	}
+	for input, want := range tests {
+		got, err := ResolveDate(input, "yyyy-mm-dd", now)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+
+	_, err := ResolveDate("someday", "yyyy-mm-dd", now)
+	assert.Error(t, err)
+
+	got, err := ResolveDate("+1d", "dd/mm/yyyy", now)
+	require.NoError(t, err)
+	assert.Equal(t, "13/03/2025", got)
+}
@@ -0,0 +1,325 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// DialectComment is the original <!--input-type:name:"desc"--> syntax
+	DialectComment = "comment"
+	// DialectGo is a text/template body with inputs declared in a header
+	DialectGo = "go"
+)
+
+// BuiltinValues are always available to templates; kira new fills them in
+var BuiltinValues = []string{"id", "title", "status", "kind", "created"}
+
+// Header is the template front matter of a Go-dialect template:
+//
+//	---
+//	template:
+//	  extends: base
+//	  inputs:
+//	    - name: estimate
+//	      type: number
+//	      description: Estimate in days
+//	---
+type Header struct {
+	Extends string  `yaml:"extends,omitempty"`
+	Inputs  []Input `yaml:"inputs"`
+}
+
+// Template is a parsed work item template in either dialect. Body is always
+// text/template source; comment-dialect templates are converted on load.
+type Template struct {
+	Path    string
+	Dialect string
+	Header  Header
+	Inputs  []Input
+	Body    string
+
+	name     string      // partial name
+	parent   *Template   // template named by extends
+	partials []*Template // partials available to the template
+}
+
+// RenderOptions supplies the environment for the template function library
+type RenderOptions struct {
+	Now    func() time.Time
+	User   func() string
+	NextID func() (string, error)
+}
+
+// DefaultRenderOptions uses the clock and the git or OS user. nextID is not
+// available unless the caller provides it.
+func DefaultRenderOptions() RenderOptions {
+	return RenderOptions{
+		Now:  time.Now,
+		User: currentUser,
+		NextID: func() (string, error) {
+			return "", fmt.Errorf("nextID is not available here")
+		},
+	}
+}
+
+// LoadTemplate reads and parses a template file, resolving the template it
+// extends and the partials next to it
+func LoadTemplate(path string) (*Template, error) {
+	t, err := loadTemplate(path, false, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+	if t.Dialect == DialectComment {
+		return t, nil
+	}
+	if t.partials, err = loadPartials(t.chain()); err != nil {
+		return nil, err
+	}
+	if err := t.collectInputs(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return t, nil
+}
+
+// ParseTemplate detects the dialect of a template and collects its inputs in
+// declaration order. Templates that extend another must be loaded from a file.
+func ParseTemplate(content string) (*Template, error) {
+	t, err := parseSource(content, false)
+	if err != nil {
+		return nil, err
+	}
+	if t.Dialect == DialectComment {
+		return t, nil
+	}
+	if t.Header.Extends != "" {
+		return nil, fmt.Errorf("extends %s needs the template to be loaded from a file", t.Header.Extends)
+	}
+	if err := t.collectInputs(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// parseSource splits a template into header and body. Without a header it is
+// a comment-dialect template, unless goOnly is set for bases and partials.
+func parseSource(content string, goOnly bool) (*Template, error) {
+	header, body, ok, err := splitHeader(content)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return &Template{Dialect: DialectGo, Header: *header, Body: body}, nil
+	}
+	if goOnly {
+		return &Template{Dialect: DialectGo, Body: content}, nil
+	}
+
+	parsed, err := ParseTemplateInputs(content)
+	if err != nil {
+		return nil, err
+	}
+	t := &Template{Dialect: DialectComment, Body: convertCommentInputs(content)}
+	for _, name := range parsed.Order {
+		t.Inputs = append(t.Inputs, parsed.Inputs[name])
+	}
+	return t, nil
+}
+
+// Render executes the template with the given values. Inputs without a value
+// render as empty, list inputs as a YAML sequence, and empty optional inputs
+// are dropped from the front matter.
+func (t *Template) Render(values map[string]string, opts RenderOptions) (string, error) {
+	data := make(map[string]interface{})
+	for _, name := range BuiltinValues {
+		data[name] = ""
+	}
+	for k, v := range values {
+		data[k] = v
+	}
+	for _, input := range t.Inputs {
+		if input.IsList() {
+			data[input.Name] = ParseList(values[input.Name])
+			continue
+		}
+		if _, ok := data[input.Name]; !ok {
+			data[input.Name] = ""
+		}
+	}
+
+	tmpl, _, err := t.compile(funcMap(data, opts))
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render template: %w", err)
+	}
+	return omitEmptyOptional(buf.String(), t.Inputs, values), nil
+}
+
+// splitHeader separates a template front matter header from the body. ok is
+// false when the file has no header, i.e. it is a comment-dialect template
+// whose leading --- block is the work item's own front matter.
+func splitHeader(content string) (*Header, string, bool, error) {
+	if !strings.HasPrefix(content, "---\n") && !strings.HasPrefix(content, "---\r\n") {
+		return nil, content, false, nil
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "---" {
+			continue
+		}
+		var doc struct {
+			Template *Header `yaml:"template"`
+		}
+		if err := yaml.Unmarshal([]byte(strings.Join(lines[1:i], "")), &doc); err != nil || doc.Template == nil {
+			return nil, content, false, nil
+		}
+		return doc.Template, strings.Join(lines[i+1:], ""), true, nil
+	}
+	return nil, content, false, nil
+}
+
+// convertCommentInputs turns the comment syntax into template actions so both
+// dialects share one renderer
+func convertCommentInputs(content string) string {
+	content = strings.ReplaceAll(content, "{{", `{{"{{"}}`)
+	return inputCommentRe.ReplaceAllStringFunc(content, func(m string) string {
+		name := inputCommentRe.FindStringSubmatch(m)[3]
+		return fmt.Sprintf("{{index . %q}}", name)
+	})
+}
+
+// walkCommands calls fn for every command and template invocation below node
+func walkCommands(node parse.Node, fn func(parse.Node)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkCommands(child, fn)
+		}
+	case *parse.ActionNode:
+		walkCommands(n.Pipe, fn)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkCommands(cmd, fn)
+		}
+	case *parse.CommandNode:
+		fn(n)
+		for _, arg := range n.Args {
+			walkCommands(arg, fn)
+		}
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, fn)
+	case *parse.TemplateNode:
+		walkCommands(n.Pipe, fn)
+		fn(n)
+	}
+}
+
+func walkBranch(n *parse.BranchNode, fn func(parse.Node)) {
+	walkCommands(n.Pipe, fn)
+	walkCommands(n.List, fn)
+	walkCommands(n.ElseList, fn)
+}
+
+var slugRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Slug turns text into lowercase kebab-case for file names, anchors and
+// branch names
+func Slug(s string) string {
+	return strings.Trim(slugRe.ReplaceAllString(strings.ToLower(s), "-"), "-")
+}
+
+// funcMap is the kira template function library. data holds the input values
+// that prompt and choose read from.
+func funcMap(data map[string]interface{}, opts RenderOptions) template.FuncMap {
+	value := func(name string) interface{} {
+		if v, ok := data[name]; ok {
+			return v
+		}
+		return ""
+	}
+	return template.FuncMap{
+		"now": func(layout ...string) string {
+			format := DefaultDateFormat
+			if len(layout) > 0 {
+				format = layout[0]
+			}
+			return opts.now().Format(DateLayout(format))
+		},
+		"user": func() string {
+			if opts.User == nil {
+				return currentUser()
+			}
+			return opts.User()
+		},
+		"nextID": func() (string, error) {
+			if opts.NextID == nil {
+				return "", fmt.Errorf("nextID is not available here")
+			}
+			return opts.NextID()
+		},
+		"slug": Slug,
+		"prompt": func(name string, _ ...string) interface{} {
+			return value(name)
+		},
+		"choose": func(name string, _ ...string) interface{} {
+			return value(name)
+		},
+		"list": func(items ...interface{}) []interface{} {
+			return items
+		},
+		"default": func(def string, v interface{}) interface{} {
+			if v == nil || fmt.Sprint(v) == "" {
+				return def
+			}
+			return v
+		},
+		"join":  strings.Join,
+		"lower": strings.ToLower,
+		"upper": strings.ToUpper,
+		"trim":  strings.TrimSpace,
+	}
+}
+
+func (o RenderOptions) now() time.Time {
+	if o.Now == nil {
+		return time.Now()
+	}
+	return o.Now()
+}
+
+// currentUser is the git user email, falling back to the OS user name
+func currentUser() string {
+	if out, err := exec.Command("git", "config", "user.email").Output(); err == nil {
+		if email := strings.TrimSpace(string(out)); email != "" {
+			return email
+		}
+	}
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	return os.Getenv("USERNAME")
+}
@@ -0,0 +1,107 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixedOptions() RenderOptions {
+	return RenderOptions{
+		Now:    func() time.Time { return time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC) },
+		User:   func() string { return "dev@example.com" },
+		NextID: func() (string, error) { return "042", nil },
+	}
+}
+
+func TestParseTemplateGoDialect(t *testing.T) {
+	content := `---
+template:
+  inputs:
+    - name: title
+      description: Feature title
+    - name: estimate
+      type: number
+      description: Estimate in days
+---
+---
+id: {{ .id }}
+title: {{ .title }}
+owner: {{ user }}
+created: {{ now }}
+slug: {{ slug .title }}
+{{- if .estimate }}
+estimate: {{ .estimate }}
+{{- end }}
+priority: {{ choose "priority" "Priority" "low" "high" }}
+---
+
+## Acceptance Criteria
+{{- range list "first" "second" }}
+- [ ] {{ . }}
+{{- end }}
+{{ prompt "context" "Background" | default "TBD" }}
+`
+
+	tmpl, err := ParseTemplate(content)
+	require.NoError(t, err)
+	assert.Equal(t, DialectGo, tmpl.Dialect)
+
+	var names []string
+	for _, input := range tmpl.Inputs {
+		names = append(names, input.Name)
+	}
+	assert.Equal(t, []string{"title", "estimate", "priority", "context"}, names)
+	assert.Equal(t, InputString, tmpl.Inputs[0].Type)
+	assert.Equal(t, []string{"low", "high"}, tmpl.Inputs[2].Options)
+
+	t.Run("renders functions, conditionals and loops", func(t *testing.T) {
+		result, err := tmpl.Render(map[string]string{"id": "001", "title": "Dark Mode!", "priority": "high"}, fixedOptions())
+		require.NoError(t, err)
+
+		assert.True(t, len(result) > 4 && result[:4] == "---\n", "header is stripped")
+		assert.Contains(t, result, "owner: dev@example.com")
+		assert.Contains(t, result, "created: 2025-03-14")
+		assert.Contains(t, result, "slug: dark-mode")
+		assert.NotContains(t, result, "estimate:")
+		assert.Contains(t, result, "priority: high")
+		assert.Contains(t, result, "- [ ] first\n- [ ] second")
+		assert.Contains(t, result, "TBD")
+	})
+
+	t.Run("rejects unknown fields", func(t *testing.T) {
+		bad, err := ParseTemplate("---\ntemplate:\n  inputs: []\n---\n{{ .missing }}\n")
+		require.NoError(t, err)
+		_, err = bad.Render(nil, fixedOptions())
+		assert.Error(t, err)
+	})
+}
+
+func TestParseTemplateCommentDialect(t *testing.T) {
+	content := `---
+id: <!--input-number:id:"Work item ID"-->
+tags: <!--input-strings[a,b]:tags:"Tags"-->
+estimate: <!--input-number:estimate:"Estimate"-->
+---
+
+# <!--input-string:title:"Title"-->
+Literal {{ braces }} stay as they are.
+`
+
+	tmpl, err := ParseTemplate(content)
+	require.NoError(t, err)
+	assert.Equal(t, DialectComment, tmpl.Dialect)
+	require.Len(t, tmpl.Inputs, 4)
+	assert.Equal(t, "id", tmpl.Inputs[0].Name)
+	assert.Equal(t, "title", tmpl.Inputs[3].Name)
+
+	result, err := tmpl.Render(map[string]string{"id": "007", "title": "Legacy"}, fixedOptions())
+	require.NoError(t, err)
+	assert.Contains(t, result, "id: 007")
+	assert.Contains(t, result, "tags: []")
+	assert.Contains(t, result, "estimate: \n", "no invented estimate")
+	assert.Contains(t, result, "# Legacy")
+	assert.Contains(t, result, "Literal {{ braces }} stay as they are.")
+}
@@ -0,0 +1,329 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// PartialsDir is the folder next to a template that holds its partials
+const PartialsDir = "partials"
+
+// rootName is the name of the template that is executed: the body of the
+// outermost base template
+const rootName = "kira"
+
+func loadTemplate(path string, goOnly bool, visiting map[string]bool) (*Template, error) {
+	key := filepath.Clean(path)
+	if visiting[key] {
+		return nil, fmt.Errorf("%s: template inheritance loops back to itself", path)
+	}
+	visiting[key] = true
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template: %w", err)
+	}
+	t, err := parseSource(string(content), goOnly)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	t.Path = path
+
+	if t.Header.Extends != "" {
+		basePath, err := resolveExtends(filepath.Dir(path), t.Header.Extends)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if t.parent, err = loadTemplate(basePath, true, visiting); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// resolveExtends finds the base template named by extends. A bare name such
+// as "base" matches base.md or template.base.md in the same folder.
+func resolveExtends(dir, name string) (string, error) {
+	candidates := []string{filepath.Join(dir, name)}
+	if filepath.Ext(name) == "" {
+		candidates = []string{
+			filepath.Join(dir, name+".md"),
+			filepath.Join(dir, "template."+name+".md"),
+		}
+	}
+	for _, c := range candidates {
+		if info, err := os.Stat(c); err == nil && !info.IsDir() {
+			return c, nil
+		}
+	}
+	return "", fmt.Errorf("base template %q not found in %s", name, dir)
+}
+
+// loadPartials reads the partials folder of every template in the chain.
+// A partial is included by its file name without .md, e.g.
+// {{ template "release-notes" . }}.
+func loadPartials(chain []*Template) ([]*Template, error) {
+	var partials []*Template
+	seenDirs := make(map[string]bool)
+	for _, t := range chain {
+		dir := filepath.Join(filepath.Dir(t.Path), PartialsDir)
+		if seenDirs[dir] {
+			continue
+		}
+		seenDirs[dir] = true
+
+		matches, err := filepath.Glob(filepath.Join(dir, "*.md"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		for _, path := range matches {
+			p, err := loadTemplate(path, true, make(map[string]bool))
+			if err != nil {
+				return nil, err
+			}
+			if p.Header.Extends != "" {
+				return nil, fmt.Errorf("%s: partials cannot extend another template", path)
+			}
+			p.name = strings.TrimSuffix(filepath.Base(path), ".md")
+			partials = append(partials, p)
+		}
+	}
+	return partials, nil
+}
+
+// chain returns the inheritance chain from the outermost base down to t
+func (t *Template) chain() []*Template {
+	var chain []*Template
+	for c := t; c != nil; c = c.parent {
+		chain = append([]*Template{c}, chain...)
+	}
+	return chain
+}
+
+// compile parses partials, then the chain from the outermost base down, into
+// one template set. Later definitions replace earlier ones, which is how a
+// template overrides the blocks of its base. owners records which file each
+// named template finally came from.
+func (t *Template) compile(fm template.FuncMap) (*template.Template, map[string]*Template, error) {
+	root := template.New(rootName).Funcs(fm).Option("missingkey=error")
+	owners := make(map[string]*Template)
+
+	add := func(name string, src *Template) error {
+		before := make(map[string]*parse.Tree)
+		for _, tt := range root.Templates() {
+			before[tt.Name()] = tt.Tree
+		}
+		target := root
+		if name != rootName {
+			target = root.New(name)
+		}
+		if _, err := target.Parse(src.Body); err != nil {
+			if src.Path != "" {
+				return fmt.Errorf("failed to parse template %s: %w", src.Path, err)
+			}
+			return fmt.Errorf("failed to parse template: %w", err)
+		}
+		for _, tt := range root.Templates() {
+			if tt.Tree != nil && before[tt.Name()] != tt.Tree {
+				owners[tt.Name()] = src
+			}
+		}
+		return nil
+	}
+
+	for _, p := range t.partials {
+		if err := add(p.name, p); err != nil {
+			return nil, nil, err
+		}
+	}
+	chain := t.chain()
+	if err := add(rootName, chain[0]); err != nil {
+		return nil, nil, err
+	}
+	for _, c := range chain[1:] {
+		if err := add("extends:"+c.Path, c); err != nil {
+			return nil, nil, err
+		}
+	}
+	return root, owners, nil
+}
+
+// collectInputs gathers inputs in the order a user meets them: the header of
+// the outermost base, then, walking the rendered structure, the header of each
+// partial or overriding template as it is entered and every prompt or choose
+// call. A header declaration for a prompt or choose call is asked at the
+// call. Inputs declared along the chain but never reached come last; unused
+// partials contribute nothing.
+func (t *Template) collectInputs() error {
+	set, owners, err := t.compile(funcMap(nil, RenderOptions{}))
+	if err != nil {
+		return err
+	}
+
+	c := inputCollector{
+		seen:      make(map[string]bool),
+		added:     make(map[*Template]bool),
+		walked:    make(map[string]bool),
+		overrides: make(map[string]Input),
+		inline:    make(map[string]bool),
+		set:       set,
+		owners:    owners,
+	}
+	chain := t.chain()
+	// The template being used has the final say over how an input is
+	// declared, then its bases, then partials
+	for i := len(chain) - 1; i >= 0; i-- {
+		for _, input := range chain[i].Header.Inputs {
+			input, err := normalizeInput(input)
+			if err != nil {
+				return err
+			}
+			if _, ok := c.overrides[input.Name]; !ok {
+				c.overrides[input.Name] = input
+			}
+		}
+	}
+	// A declaration for a prompt or choose call refines that call and is
+	// asked where the call is
+	for _, tmpl := range set.Templates() {
+		if tmpl.Tree == nil {
+			continue
+		}
+		walkCommands(tmpl.Tree.Root, func(node parse.Node) {
+			if cmd, ok := node.(*parse.CommandNode); ok {
+				if input, ok := inlineInput(cmd); ok {
+					c.inline[input.Name] = true
+				}
+			}
+		})
+	}
+	if err := c.addHeader(chain[0]); err != nil {
+		return err
+	}
+	if err := c.walk(rootName); err != nil {
+		return err
+	}
+	for _, src := range chain {
+		if err := c.addHeader(src); err != nil {
+			return err
+		}
+	}
+	t.Inputs = c.inputs
+	return nil
+}
+
+type inputCollector struct {
+	inputs    []Input
+	seen      map[string]bool
+	added     map[*Template]bool
+	walked    map[string]bool
+	overrides map[string]Input
+	inline    map[string]bool
+	set       *template.Template
+	owners    map[string]*Template
+}
+
+func (c *inputCollector) add(input Input) {
+	if override, ok := c.overrides[input.Name]; ok {
+		if override.Description == "" {
+			override.Description = input.Description
+		}
+		input = override
+	}
+	if !c.seen[input.Name] {
+		c.seen[input.Name] = true
+		c.inputs = append(c.inputs, input)
+	}
+}
+
+func (c *inputCollector) addHeader(src *Template) error {
+	if src == nil || c.added[src] {
+		return nil
+	}
+	c.added[src] = true
+	for _, input := range src.Header.Inputs {
+		input, err := normalizeInput(input)
+		if err != nil {
+			return err
+		}
+		if c.inline[input.Name] {
+			continue
+		}
+		c.add(input)
+	}
+	return nil
+}
+
+// normalizeInput fills in the defaults of a header input declaration
+func normalizeInput(input Input) (Input, error) {
+	if input.Name == "" {
+		return input, fmt.Errorf("template input without a name")
+	}
+	if input.Type == "" {
+		input.Type = InputString
+	}
+	if input.Type == InputDateTime && input.DateFormat == "" {
+		input.DateFormat = DefaultDateFormat
+	}
+	return input, nil
+}
+
+func (c *inputCollector) walk(name string) error {
+	if c.walked[name] {
+		return nil
+	}
+	c.walked[name] = true
+	tmpl := c.set.Lookup(name)
+	if tmpl == nil || tmpl.Tree == nil {
+		return nil
+	}
+
+	var err error
+	walkCommands(tmpl.Tree.Root, func(node parse.Node) {
+		if err != nil {
+			return
+		}
+		switch n := node.(type) {
+		case *parse.TemplateNode:
+			if err = c.addHeader(c.owners[n.Name]); err == nil {
+				err = c.walk(n.Name)
+			}
+		case *parse.CommandNode:
+			if input, ok := inlineInput(n); ok {
+				c.add(input)
+			}
+		}
+	})
+	return err
+}
+
+// inlineInput turns a prompt or choose call into an input declaration
+func inlineInput(cmd *parse.CommandNode) (Input, bool) {
+	ident, ok := cmd.Args[0].(*parse.IdentifierNode)
+	if !ok || (ident.Ident != "prompt" && ident.Ident != "choose") {
+		return Input{}, false
+	}
+	var args []string
+	for _, arg := range cmd.Args[1:] {
+		if s, ok := arg.(*parse.StringNode); ok {
+			args = append(args, s.Text)
+		}
+	}
+	if len(args) == 0 {
+		return Input{}, false
+	}
+	input := Input{Type: InputString, Name: args[0], Description: args[0]}
+	if len(args) > 1 {
+		input.Description = args[1]
+	}
+	if ident.Ident == "choose" && len(args) > 2 {
+		input.Options = args[2:]
+	}
+	return input, true
+}
@@ -0,0 +1,95 @@
+package templates
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateInheritance(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	write("base.md", `{{ template "fields" . }}
+# {{ .title }}
+{{ block "body" . }}default body{{ end }}
+`)
+	write("partials/fields.md", `---
+template:
+  inputs:
+    - name: priority
+      options: [low, high]
+      description: Priority
+---
+priority: {{ .priority }}`)
+	write("partials/unused.md", "{{ prompt \"never\" \"Never asked\" }}")
+	write("template.task.md", `---
+template:
+  extends: base
+  inputs:
+    - name: priority
+      options: [low, medium, high]
+      default: medium
+      description: Task priority
+    - name: notes
+      multiline: true
+---
+{{ define "body" }}{{ prompt "steps" "Steps" }}{{ prompt "notes" "Notes" }}{{ end }}
+`)
+	write("template.note.md", "---\ntemplate:\n  extends: base\n---\n")
+
+	t.Run("overrides blocks and includes partials", func(t *testing.T) {
+		tmpl, err := LoadTemplate(filepath.Join(dir, "template.task.md"))
+		require.NoError(t, err)
+
+		require.Len(t, tmpl.Inputs, 3)
+		assert.Equal(t, "priority", tmpl.Inputs[0].Name)
+		assert.Equal(t, "Task priority", tmpl.Inputs[0].Description, "the kind's declaration wins")
+		assert.Equal(t, "steps", tmpl.Inputs[1].Name)
+		assert.False(t, tmpl.Inputs[1].Multiline, "only declared inputs are multiline")
+		assert.Equal(t, "notes", tmpl.Inputs[2].Name, "a declared prompt is asked at the call")
+		assert.Equal(t, "Notes", tmpl.Inputs[2].Description)
+		assert.True(t, tmpl.Inputs[2].Multiline)
+
+		values := map[string]string{"title": "Ship it", "steps": "Build"}
+		tmpl.ApplyDefaults(values)
+		result, err := tmpl.Render(values, fixedOptions())
+		require.NoError(t, err)
+		assert.Equal(t, "priority: medium\n# Ship it\nBuild\n", result)
+	})
+
+	t.Run("keeps base blocks that are not overridden", func(t *testing.T) {
+		tmpl, err := LoadTemplate(filepath.Join(dir, "template.note.md"))
+		require.NoError(t, err)
+		result, err := tmpl.Render(map[string]string{"title": "Note", "priority": "low"}, fixedOptions())
+		require.NoError(t, err)
+		assert.Contains(t, result, "default body")
+	})
+
+	t.Run("reports inheritance loops", func(t *testing.T) {
+		write("loop.md", "---\ntemplate:\n  extends: loop\n---\n")
+		_, err := LoadTemplate(filepath.Join(dir, "loop.md"))
+		assert.Error(t, err)
+	})
+}
+
+func TestDefaultTemplatesRender(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, CreateDefaultTemplates(dir))
+
+	for _, kind := range []string{"prd", "issue", "spike", "task"} {
+		tmpl, err := LoadTemplate(filepath.Join(dir, "templates", "template."+kind+".md"))
+		require.NoError(t, err, kind)
+		result, err := tmpl.Render(map[string]string{"id": "001", "title": "Item", "status": "todo", "kind": kind}, fixedOptions())
+		require.NoError(t, err, kind)
+		assert.Contains(t, result, "kind: "+kind)
+		assert.Contains(t, result, "## Release Notes")
+	}
+}
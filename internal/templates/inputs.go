@@ -0,0 +1,224 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// List is the value of a strings input. It ranges like a slice and prints as
+// a YAML flow sequence, so "tags: {{ .tags }}" renders as "tags: [a, b]".
+type List []string
+
+func (l List) String() string {
+	items := make([]string, len(l))
+	for i, item := range l {
+		items[i] = yamlScalar(item)
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// ParseList splits a comma-separated value, also accepting the [a, b] form
+func ParseList(value string) List {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+	var items List
+	for _, item := range strings.Split(value, ",") {
+		item = strings.Trim(strings.TrimSpace(item), `"'`)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// IsList reports whether the input takes several values
+func (i Input) IsList() bool {
+	return i.Type == InputStrings || i.Multi
+}
+
+// Normalize turns a raw value into its canonical form: relative dates are
+// resolved and lists are re-joined
+func (i Input) Normalize(value string, now time.Time) (string, error) {
+	switch {
+	case strings.TrimSpace(value) == "":
+		return "", nil
+	case i.Type == InputDateTime:
+		return ResolveDate(value, i.DateFormat, now)
+	case i.IsList():
+		return strings.Join(ParseList(value), ", "), nil
+	}
+	return strings.TrimSpace(value), nil
+}
+
+// NormalizeValues normalizes the value of every input in place
+func (t *Template) NormalizeValues(values map[string]string, now time.Time) error {
+	for _, input := range t.Inputs {
+		value, ok := values[input.Name]
+		if !ok {
+			continue
+		}
+		normalized, err := input.Normalize(value, now)
+		if err != nil {
+			return fmt.Errorf("%s: %w", input.Name, err)
+		}
+		values[input.Name] = normalized
+	}
+	return nil
+}
+
+// Validate checks a value against the input declaration. An empty value is
+// only an error when the input is required.
+func (i Input) Validate(value string) error {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		if i.Required {
+			return fmt.Errorf("%s is required", i.Name)
+		}
+		return nil
+	}
+
+	if len(i.Options) > 0 {
+		items := []string{value}
+		if i.IsList() {
+			items = ParseList(value)
+		}
+		for _, item := range items {
+			if !containsOption(i.Options, item) {
+				return fmt.Errorf("%s: %q is not one of %s", i.Name, item, strings.Join(i.Options, ", "))
+			}
+		}
+	}
+
+	if i.Type == InputDateTime {
+		if _, err := time.Parse(DateLayout(i.DateFormat), value); err != nil {
+			return fmt.Errorf("%s: %q does not match the date format %s", i.Name, value, i.DateFormat)
+		}
+	}
+
+	if i.Type == InputNumber {
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a number", i.Name, value)
+		}
+		if i.Min != nil && n < *i.Min {
+			return fmt.Errorf("%s: %s is below the minimum of %s", i.Name, value, formatNumber(*i.Min))
+		}
+		if i.Max != nil && n > *i.Max {
+			return fmt.Errorf("%s: %s is above the maximum of %s", i.Name, value, formatNumber(*i.Max))
+		}
+	}
+
+	if i.Pattern != "" {
+		re, err := regexp.Compile("^(?:" + i.Pattern + ")$")
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern %q: %w", i.Name, i.Pattern, err)
+		}
+		if !re.MatchString(value) {
+			return fmt.Errorf("%s: %q does not match %s", i.Name, value, i.Pattern)
+		}
+	}
+
+	return nil
+}
+
+// ApplyDefaults fills inputs that have no value with their declared default
+func (t *Template) ApplyDefaults(values map[string]string) {
+	for _, input := range t.Inputs {
+		if input.Default == "" {
+			continue
+		}
+		if v, ok := values[input.Name]; !ok || strings.TrimSpace(v) == "" {
+			values[input.Name] = input.Default
+		}
+	}
+}
+
+// MissingRequired returns the required inputs that have no value
+func (t *Template) MissingRequired(values map[string]string) []Input {
+	var missing []Input
+	for _, input := range t.Inputs {
+		if input.Required && strings.TrimSpace(values[input.Name]) == "" {
+			missing = append(missing, input)
+		}
+	}
+	return missing
+}
+
+// ValidateValues checks every provided value and reports all problems at once
+func (t *Template) ValidateValues(values map[string]string) error {
+	var problems []string
+	for _, input := range t.Inputs {
+		if err := input.Validate(values[input.Name]); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid inputs:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// omitEmptyOptional drops front matter keys of optional inputs that were
+// left empty, so items don't carry placeholder fields like "due:"
+func omitEmptyOptional(content string, inputs []Input, values map[string]string) string {
+	omit := make(map[string]bool)
+	for _, input := range inputs {
+		if input.Optional && strings.TrimSpace(values[input.Name]) == "" {
+			omit[input.Name] = true
+		}
+	}
+	if len(omit) == 0 {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return content
+	}
+	result := []string{lines[0]}
+	inFrontMatter := true
+	for _, line := range lines[1:] {
+		if inFrontMatter && strings.TrimSpace(line) == "---" {
+			inFrontMatter = false
+		}
+		if inFrontMatter {
+			if key, value, ok := strings.Cut(line, ":"); ok && omit[strings.TrimSpace(key)] && isEmptyYAML(value) {
+				continue
+			}
+		}
+		result = append(result, line)
+	}
+	return strings.Join(result, "\n")
+}
+
+func isEmptyYAML(value string) bool {
+	switch strings.TrimSpace(value) {
+	case "", `""`, "''", "[]", "null", "~":
+		return true
+	}
+	return false
+}
+
+func containsOption(options []string, value string) bool {
+	for _, o := range options {
+		if strings.TrimSpace(o) == value {
+			return true
+		}
+	}
+	return false
+}
+
+func formatNumber(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// yamlScalar quotes a list item when it would not survive as a plain scalar
+func yamlScalar(s string) string {
+	if s == "" || strings.ContainsAny(s, ",[]{}:#&*!|>'\"%@`") || strings.TrimSpace(s) != s {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,92 @@
+package templates
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputValidate(t *testing.T) {
+	min, max := 0.5, 10.0
+	estimate := Input{Name: "estimate", Type: InputNumber, Min: &min, Max: &max}
+	assert.NoError(t, estimate.Validate(""))
+	assert.NoError(t, estimate.Validate("2.5"))
+	assert.Error(t, estimate.Validate("abc"))
+	assert.Error(t, estimate.Validate("0"))
+	assert.Error(t, estimate.Validate("11"))
+
+	owner := Input{Name: "owner", Required: true, Pattern: `[a-z]+@example\.com`}
+	assert.Error(t, owner.Validate(""))
+	assert.Error(t, owner.Validate("someone@other.com"))
+	assert.NoError(t, owner.Validate("dev@example.com"))
+
+	tags := Input{Name: "tags", Options: []string{"ui", "api"}, Multi: true}
+	assert.NoError(t, tags.Validate("ui, api"))
+	assert.Error(t, tags.Validate("ui,db"))
+}
+
+func TestRequiredDefaultAndOptionalInputs(t *testing.T) {
+	tmpl, err := ParseTemplate(`---
+template:
+  inputs:
+    - name: estimate
+      type: number
+      required: true
+      description: Estimate in days
+    - name: priority
+      default: medium
+      description: Priority
+    - name: due
+      type: datetime
+      optional: true
+      description: Due date
+---
+---
+estimate: {{ .estimate }}
+priority: {{ .priority }}
+due: {{ .due }}
+---
+`)
+	require.NoError(t, err)
+
+	values := map[string]string{}
+	tmpl.ApplyDefaults(values)
+	assert.Equal(t, "medium", values["priority"])
+
+	missing := tmpl.MissingRequired(values)
+	require.Len(t, missing, 1)
+	assert.Equal(t, "estimate", missing[0].Name)
+	assert.Error(t, tmpl.ValidateValues(values))
+
+	values["estimate"] = "3"
+	require.NoError(t, tmpl.ValidateValues(values))
+	result, err := tmpl.Render(values, fixedOptions())
+	require.NoError(t, err)
+	assert.Equal(t, "---\nestimate: 3\npriority: medium\n---\n", result)
+}
+
+func TestListInputs(t *testing.T) {
+	tmpl, err := ParseTemplate(`---
+tags: <!--input-strings[ui,api,db]:tags:"Tags"-->
+labels: <!--input-strings:labels:"Labels"-->
+---
+`)
+	require.NoError(t, err)
+	assert.Equal(t, InputStrings, tmpl.Inputs[0].Type)
+
+	values := map[string]string{"tags": "[ui, api]", "labels": "needs review, v2: beta"}
+	require.NoError(t, tmpl.NormalizeValues(values, time.Now()))
+	require.NoError(t, tmpl.ValidateValues(values))
+	assert.Error(t, tmpl.ValidateValues(map[string]string{"tags": "ui, web"}))
+
+	result, err := tmpl.Render(values, fixedOptions())
+	require.NoError(t, err)
+	assert.Contains(t, result, "tags: [ui, api]\n")
+	assert.Contains(t, result, `labels: [needs review, "v2: beta"]`)
+
+	result, err = tmpl.Render(nil, fixedOptions())
+	require.NoError(t, err)
+	assert.Contains(t, result, "tags: []\n")
+}
@@ -0,0 +1,107 @@
+package templates
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// PackManifestFile describes a template pack at the root of its source
+	PackManifestFile = "pack.yml"
+	// UpstreamDir holds the unmodified copy of an installed pack, the common
+	// ancestor when merging an update into local changes
+	UpstreamDir = ".upstream"
+)
+
+// Pack is a set of templates shared between repositories:
+//
+//	name: acme
+//	version: 1.2.0
+//	kinds:
+//	  bug: template.bug.md
+type Pack struct {
+	Name        string            `yaml:"name"`
+	Version     string            `yaml:"version"`
+	Description string            `yaml:"description,omitempty"`
+	Kinds       map[string]string `yaml:"kinds"`
+}
+
+// ReadPack loads the pack in dir. Without a pack.yml the pack is named after
+// the folder and every template.<kind>.md file is a kind.
+func ReadPack(dir string) (Pack, error) {
+	var pack Pack
+	data, err := os.ReadFile(filepath.Join(dir, PackManifestFile))
+	switch {
+	case err == nil:
+		if err := yaml.Unmarshal(data, &pack); err != nil {
+			return pack, fmt.Errorf("failed to parse %s: %w", filepath.Join(dir, PackManifestFile), err)
+		}
+	case !os.IsNotExist(err):
+		return pack, err
+	}
+
+	if pack.Name == "" {
+		pack.Name = filepath.Base(filepath.Clean(dir))
+	}
+	if len(pack.Kinds) == 0 {
+		matches, err := filepath.Glob(filepath.Join(dir, "template.*.md"))
+		if err != nil {
+			return pack, err
+		}
+		pack.Kinds = make(map[string]string)
+		for _, m := range matches {
+			file := filepath.Base(m)
+			kind := strings.TrimSuffix(strings.TrimPrefix(file, "template."), ".md")
+			pack.Kinds[kind] = file
+		}
+	}
+	if len(pack.Kinds) == 0 {
+		return pack, fmt.Errorf("%s contains no templates: add a %s or template.<kind>.md files", dir, PackManifestFile)
+	}
+	for kind, file := range pack.Kinds {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			return pack, fmt.Errorf("pack %s: template %s for kind %s not found", pack.Name, file, kind)
+		}
+	}
+	return pack, nil
+}
+
+// SortedKinds returns the pack's kinds in name order
+func (p Pack) SortedKinds() []string {
+	var kinds []string
+	for k := range p.Kinds {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// PackFiles lists the files of a pack relative to dir, skipping version
+// control metadata and the upstream copy
+func PackFiles(dir string) ([]string, error) {
+	var files []string
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if path != dir && (info.Name() == ".git" || info.Name() == UpstreamDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	})
+	sort.Strings(files)
+	return files, err
+}
@@ -6,7 +6,6 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
-	"time"
 )
 
 type InputType string
@@ -15,27 +14,38 @@ const (
 	InputString   InputType = "string"
 	InputNumber   InputType = "number"
 	InputDateTime InputType = "datetime"
+	InputStrings  InputType = "strings"
 )
 
 type Input struct {
-	Type        InputType
-	Name        string
-	Description string
-	Options     []string
-	DateFormat  string
+	Type        InputType `yaml:"type"`
+	Name        string    `yaml:"name"`
+	Description string    `yaml:"description"`
+	Options     []string  `yaml:"options,omitempty"`
+	DateFormat  string    `yaml:"format,omitempty"`
+	Required    bool      `yaml:"required,omitempty"`
+	Default     string    `yaml:"default,omitempty"`
+	Pattern     string    `yaml:"pattern,omitempty"`
+	Min         *float64  `yaml:"min,omitempty"`
+	Max         *float64  `yaml:"max,omitempty"`
+	Multi       bool      `yaml:"multi,omitempty"`     // allow several options; implied by strings
+	Optional    bool      `yaml:"optional,omitempty"`  // omit the front matter key when empty
+	Multiline   bool      `yaml:"multiline,omitempty"` // free text that may span lines, such as a section
 }
 
 type TemplateInput struct {
 	Inputs map[string]Input
+	Order  []string // input names in the order they first appear
 }
 
+// Regex to match input comments: <!--input-type:variable-name:"description"-->
+var inputCommentRe = regexp.MustCompile(`<!--input-(\w+)(?:\[([^\]]+)\])?:([^:]+):"([^"]+)"-->`)
+
 func ParseTemplateInputs(content string) (*TemplateInput, error) {
 	inputs := make(map[string]Input)
+	var order []string
 	
-	// Regex to match input comments: <!--input-type:variable-name:"description"-->
-	re := regexp.MustCompile(`<!--input-(\w+)(?:\[([^\]]+)\])?:([^:]+):"([^"]+)"-->`)
-	
-	matches := re.FindAllStringSubmatch(content, -1)
+	matches := inputCommentRe.FindAllStringSubmatch(content, -1)
 	for _, match := range matches {
 		if len(match) != 5 {
 			continue
@@ -66,7 +76,7 @@ func ParseTemplateInputs(content string) (*TemplateInput, error) {
 				input.DateFormat = "2006-01-02"
 			}
 		case "strings":
-			input.Type = InputString
+			input.Type = InputStrings
 			if options != "" {
 				input.Options = strings.Split(options, ",")
 			}
@@ -74,82 +84,47 @@ func ParseTemplateInputs(content string) (*TemplateInput, error) {
 			return nil, fmt.Errorf("unknown input type: %s", inputType)
 		}
 		
+		if _, seen := inputs[name]; !seen {
+			order = append(order, name)
+		}
 		inputs[name] = input
 	}
 	
-	return &TemplateInput{Inputs: inputs}, nil
+	return &TemplateInput{Inputs: inputs, Order: order}, nil
 }
 
+// ProcessTemplate renders a template file of either dialect with the given
+// input values
 func ProcessTemplate(templatePath string, inputs map[string]string) (string, error) {
-	content, err := os.ReadFile(templatePath)
+	tmpl, err := LoadTemplate(templatePath)
 	if err != nil {
-		return "", fmt.Errorf("failed to read template: %w", err)
-	}
-	
-	result := string(content)
-	
-	// Replace input placeholders with provided values
-	for name, value := range inputs {
-		placeholder := fmt.Sprintf("<!--input-\\w+(?:\\[[^\\]]+\\])?:%s:\"[^\"]+\"-->", name)
-		re := regexp.MustCompile(placeholder)
-		result = re.ReplaceAllString(result, value)
+		return "", err
 	}
-	
-	// Replace any remaining input placeholders with defaults
-	result = replaceRemainingInputs(result)
-	
-	return result, nil
-}
-
-func replaceRemainingInputs(content string) string {
-	// Replace string inputs with empty string
-	re := regexp.MustCompile(`<!--input-string(?:\[[^\]]+\])?:([^:]+):"[^"]+"-->`)
-	content = re.ReplaceAllString(content, "")
-	
-	// Replace number inputs with 0
-	re = regexp.MustCompile(`<!--input-number:([^:]+):"[^"]+"-->`)
-	content = re.ReplaceAllString(content, "0")
-	
-	// Replace datetime inputs with current date
-	re = regexp.MustCompile(`<!--input-datetime(?:\[[^\]]+\])?:([^:]+):"[^"]+"-->`)
-	content = re.ReplaceAllString(content, time.Now().Format("2006-01-02"))
-	
-	// Replace strings inputs with empty array
-	re = regexp.MustCompile(`<!--input-strings(?:\[[^\]]+\])?:([^:]+):"[^"]+"-->`)
-	content = re.ReplaceAllString(content, "[]")
-	
-	return content
+	return tmpl.Render(inputs, DefaultRenderOptions())
 }
 
+// GetTemplateInputs returns the inputs of a template file in declaration order
 func GetTemplateInputs(templatePath string) ([]Input, error) {
-	content, err := os.ReadFile(templatePath)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read template: %w", err)
-	}
-	
-	templateInput, err := ParseTemplateInputs(string(content))
+	tmpl, err := LoadTemplate(templatePath)
 	if err != nil {
 		return nil, err
 	}
-	
-	var inputs []Input
-	for _, input := range templateInput.Inputs {
-		inputs = append(inputs, input)
-	}
-	
-	return inputs, nil
+	return tmpl.Inputs, nil
 }
 
 func CreateDefaultTemplates(basePath string) error {
 	templates := map[string]string{
-		"template.prd.md":   getPRDTemplate(),
-		"template.issue.md": getIssueTemplate(),
-		"template.spike.md": getSpikeTemplate(),
-		"template.task.md":  getTaskTemplate(),
+		"base.md":                   getBaseTemplate(),
+		"partials/front-matter.md":  getFrontMatterPartial(),
+		"partials/release-notes.md": getReleaseNotesPartial(),
+		"template.prd.md":           getPRDTemplate(),
+		"template.issue.md":         getIssueTemplate(),
+		"template.spike.md":         getSpikeTemplate(),
+		"template.task.md":          getTaskTemplate(),
 	}
 	
 	templatesDir := filepath.Join(basePath, "templates")
-	if err := os.MkdirAll(templatesDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Join(templatesDir, PartialsDir), 0755); err != nil {
 		return fmt.Errorf("failed to create templates directory: %w", err)
 	}
 	
@@ -163,140 +138,201 @@ func CreateDefaultTemplates(basePath string) error {
 	return nil
 }
 
-func getPRDTemplate() string {
+// getBaseTemplate is the layout every default kind extends. Fields shared by
+// all kinds belong in partials/front-matter.md.
+func getBaseTemplate() string {
+	return `{{- /* Shared layout. Kinds override the "fields" and "body" blocks. */ -}}
+{{ template "front-matter" . }}
+# {{ .title }}
+{{ block "body" . }}{{ end }}
+{{ template "release-notes" . }}`
+}
+
+func getFrontMatterPartial() string {
 	return `---
-id: <!--input-number:id:"Work item ID"-->
-title: <!--input-string:title:"Feature title"-->
-status: <!--input-string[backlog,todo,doing,review,done,released,abandoned,archived]:status:"Current status"-->
-kind: prd
-assigned: <!--input-string:assigned:"Assigned to (email)"-->
-estimate: <!--input-number:estimate:"Estimate in days"-->
-created: <!--input-datetime[yyyy-mm-dd]:created:"Creation date"-->
-due: <!--input-datetime[yyyy-mm-dd]:due:"Due date (optional)"-->
-tags: <!--input-strings[frontend,backend,database,api,ui,security]:tags:"Tags"-->
+template:
+  inputs:
+    - name: assigned
+      description: Assigned to (email)
+    - name: estimate
+      type: number
+      description: Estimate in days
+    - name: tags
+      type: strings
+      description: Tags
+---
+---
+id: {{ .id }}
+title: {{ .title }}
+status: {{ .status }}
+kind: {{ .kind }}
+assigned: {{ .assigned }}
+estimate: {{ .estimate }}
+created: {{ .created }}{{ block "fields" . }}{{ end }}
+tags: {{ .tags }}
 ---
+`
+}
 
-# <!--input-string:title:"Feature title"-->
+func getReleaseNotesPartial() string {
+	return `---
+template:
+  inputs:
+    - name: release_notes
+      multiline: true
+---
+## Release Notes
+{{ prompt "release_notes" "Public-facing changes (optional)" }}
+`
+}
 
+func getPRDTemplate() string {
+	return `---
+template:
+  extends: base
+  inputs:
+    - name: due
+      type: datetime
+      format: yyyy-mm-dd
+      optional: true
+      description: Due date (optional)
+    - name: tags
+      type: strings
+      options: [frontend, backend, database, api, ui, security]
+      description: Tags
+    - name: context
+      multiline: true
+    - name: requirements
+      multiline: true
+    - name: implementation
+      multiline: true
+---
+{{ define "fields" }}
+due: {{ .due }}{{ end }}
+
+{{ define "body" }}
 ## Context
-<!--input-string:context:"Background and rationale"-->
+{{ prompt "context" "Background and rationale" }}
 
 ## Requirements
-<!--input-string:requirements:"Functional requirements"-->
+{{ prompt "requirements" "Functional requirements" }}
 
 ## Acceptance Criteria
-- [ ] <!--input-string:criteria1:"First acceptance criterion"-->
-- [ ] <!--input-string:criteria2:"Second acceptance criterion"-->
+- [ ] {{ prompt "criteria1" "First acceptance criterion" }}
+- [ ] {{ prompt "criteria2" "Second acceptance criterion" }}
 
 ## Implementation Notes
-<!--input-string:implementation:"Technical implementation details"-->
-
-## Release Notes
-<!--input-string:release_notes:"Public-facing changes (optional)"-->
+{{ prompt "implementation" "Technical implementation details" }}
+{{ end }}
 `
 }
 
 func getIssueTemplate() string {
 	return `---
-id: <!--input-number:id:"Issue ID"-->
-title: <!--input-string:title:"Issue title"-->
-status: <!--input-string[backlog,todo,doing,review,done,released,abandoned,archived]:status:"Current status"-->
-kind: issue
-assigned: <!--input-string:assigned:"Assigned to (email)"-->
-estimate: <!--input-number:estimate:"Estimate in days"-->
-created: <!--input-datetime[yyyy-mm-dd]:created:"Creation date"-->
-tags: <!--input-strings[bug,performance,security,ui]:tags:"Tags"-->
+template:
+  extends: base
+  inputs:
+    - name: tags
+      type: strings
+      options: [bug, performance, security, ui]
+      description: Tags
+    - name: problem
+      multiline: true
+    - name: expected
+      multiline: true
+    - name: actual
+      multiline: true
+    - name: solution
+      multiline: true
 ---
-
-# <!--input-string:title:"Issue title"-->
-
+{{ define "body" }}
 ## Problem Description
-<!--input-string:problem:"What is the problem?"-->
+{{ prompt "problem" "What is the problem?" }}
 
 ## Steps to Reproduce
-1. <!--input-string:step1:"First step"-->
-2. <!--input-string:step2:"Second step"-->
-3. <!--input-string:step3:"Third step"-->
+1. {{ prompt "step1" "First step" }}
+2. {{ prompt "step2" "Second step" }}
+3. {{ prompt "step3" "Third step" }}
 
 ## Expected Behavior
-<!--input-string:expected:"What should happen?"-->
+{{ prompt "expected" "What should happen?" }}
 
 ## Actual Behavior
-<!--input-string:actual:"What actually happens?"-->
+{{ prompt "actual" "What actually happens?" }}
 
 ## Solution
-<!--input-string:solution:"Proposed solution"-->
-
-## Release Notes
-<!--input-string:release_notes:"Public-facing changes (optional)"-->
+{{ prompt "solution" "Proposed solution" }}
+{{ end }}
 `
 }
 
 func getSpikeTemplate() string {
 	return `---
-id: <!--input-number:id:"Spike ID"-->
-title: <!--input-string:title:"Spike title"-->
-status: <!--input-string[backlog,todo,doing,review,done,released,abandoned,archived]:status:"Current status"-->
-kind: spike
-assigned: <!--input-string:assigned:"Assigned to (email)"-->
-estimate: <!--input-number:estimate:"Estimate in days"-->
-created: <!--input-datetime[yyyy-mm-dd]:created:"Creation date"-->
-tags: <!--input-strings[research,discovery,investigation]:tags:"Tags"-->
+template:
+  extends: base
+  inputs:
+    - name: tags
+      type: strings
+      options: [research, discovery, investigation]
+      description: Tags
+    - name: objective
+      multiline: true
+    - name: approach
+      multiline: true
+    - name: findings
+      multiline: true
+    - name: recommendations
+      multiline: true
 ---
-
-# <!--input-string:title:"Spike title"-->
-
+{{ define "body" }}
 ## Objective
-<!--input-string:objective:"What are we trying to understand?"-->
+{{ prompt "objective" "What are we trying to understand?" }}
 
 ## Questions to Answer
-- <!--input-string:question1:"First question"-->
-- <!--input-string:question2:"Second question"-->
+- {{ prompt "question1" "First question" }}
+- {{ prompt "question2" "Second question" }}
 
 ## Approach
-<!--input-string:approach:"How will we investigate?"-->
+{{ prompt "approach" "How will we investigate?" }}
 
 ## Findings
-<!--input-string:findings:"What did we discover?"-->
+{{ prompt "findings" "What did we discover?" }}
 
 ## Recommendations
-<!--input-string:recommendations:"What should we do next?"-->
-
-## Release Notes
-<!--input-string:release_notes:"Public-facing changes (optional)"-->
+{{ prompt "recommendations" "What should we do next?" }}
+{{ end }}
 `
 }
 
 func getTaskTemplate() string {
 	return `---
-id: <!--input-number:id:"Task ID"-->
-title: <!--input-string:title:"Task title"-->
-status: <!--input-string[backlog,todo,doing,review,done,released,abandoned,archived]:status:"Current status"-->
-kind: task
-assigned: <!--input-string:assigned:"Assigned to (email)"-->
-estimate: <!--input-number:estimate:"Estimate in days"-->
-created: <!--input-datetime[yyyy-mm-dd]:created:"Creation date"-->
-tags: <!--input-strings[implementation,maintenance,refactoring]:tags:"Tags"-->
+template:
+  extends: base
+  inputs:
+    - name: tags
+      type: strings
+      options: [implementation, maintenance, refactoring]
+      description: Tags
+    - name: description
+      multiline: true
+    - name: notes
+      multiline: true
 ---
-
-# <!--input-string:title:"Task title"-->
-
+{{ define "body" }}
 ## Description
-<!--input-string:description:"What needs to be done?"-->
+{{ prompt "description" "What needs to be done?" }}
 
 ## Steps
-1. <!--input-string:step1:"First step"-->
-2. <!--input-string:step2:"Second step"-->
-3. <!--input-string:step3:"Third step"-->
+1. {{ prompt "step1" "First step" }}
+2. {{ prompt "step2" "Second step" }}
+3. {{ prompt "step3" "Third step" }}
 
 ## Definition of Done
-- [ ] <!--input-string:done1:"First completion criterion"-->
-- [ ] <!--input-string:done2:"Second completion criterion"-->
+- [ ] {{ prompt "done1" "First completion criterion" }}
+- [ ] {{ prompt "done2" "Second completion criterion" }}
 
 ## Notes
-<!--input-string:notes:"Additional notes"-->
-
-## Release Notes
-<!--input-string:release_notes:"Public-facing changes (optional)"-->
+{{ prompt "notes" "Additional notes" }}
+{{ end }}
 `
 }
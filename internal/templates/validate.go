@@ -0,0 +1,163 @@
+package templates
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var inputNameRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Check reports problems with the template: malformed input declarations,
+// front matter that does not render as valid YAML, and required fields the
+// template can never produce even with every input filled in.
+func (t *Template) Check(requiredFields []string) []string {
+	var problems []string
+	for _, input := range t.Inputs {
+		for _, p := range checkInput(input) {
+			problems = append(problems, fmt.Sprintf("input %s: %s", input.Name, p))
+		}
+	}
+
+	now := time.Now()
+	filled := make(map[string]string)
+	for _, input := range t.Inputs {
+		filled[input.Name] = sampleValue(input, now)
+	}
+	empty := make(map[string]string)
+
+	for _, run := range []struct {
+		label  string
+		values map[string]string
+	}{
+		{"with all inputs filled", filled},
+		{"with no inputs", empty},
+	} {
+		values := map[string]string{
+			"id":      "001",
+			"title":   "Sample",
+			"status":  "backlog",
+			"kind":    "sample",
+			"created": now.Format(DefaultDateFormat),
+		}
+		for k, v := range run.values {
+			values[k] = v
+		}
+		t.ApplyDefaults(values)
+
+		content, err := t.Render(values, RenderOptions{Now: func() time.Time { return now }, User: func() string { return "user@example.com" }, NextID: func() (string, error) { return "002", nil }})
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("does not render %s: %v", run.label, err))
+			continue
+		}
+		frontMatter, ok := splitFrontMatter(content)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("renders without front matter %s", run.label))
+			continue
+		}
+		var fields map[string]interface{}
+		if err := yaml.Unmarshal([]byte(frontMatter), &fields); err != nil {
+			problems = append(problems, fmt.Sprintf("front matter is not valid YAML %s: %v", run.label, err))
+			continue
+		}
+
+		if run.label != "with all inputs filled" {
+			continue
+		}
+		for _, field := range requiredFields {
+			if v, ok := fields[field]; !ok || v == nil || fmt.Sprint(v) == "" {
+				problems = append(problems, fmt.Sprintf("never produces required field %s", field))
+			}
+		}
+	}
+
+	return problems
+}
+
+func checkInput(input Input) []string {
+	var problems []string
+	if !inputNameRe.MatchString(input.Name) {
+		problems = append(problems, "name must start with a letter and contain only letters, digits, _ and -")
+	}
+	switch input.Type {
+	case InputString, InputStrings, InputNumber, InputDateTime:
+	default:
+		problems = append(problems, fmt.Sprintf("unknown type %q", input.Type))
+	}
+	if input.Description == "" {
+		problems = append(problems, "missing description")
+	}
+	if len(input.Options) > 0 && input.Type != InputString && input.Type != InputStrings {
+		problems = append(problems, "options only apply to string and strings inputs")
+	}
+	if input.Multi && len(input.Options) == 0 {
+		problems = append(problems, "multi needs options")
+	}
+	if input.DateFormat != "" && input.Type != InputDateTime {
+		problems = append(problems, "format only applies to datetime inputs")
+	}
+	if (input.Min != nil || input.Max != nil) && input.Type != InputNumber {
+		problems = append(problems, "min and max only apply to number inputs")
+	}
+	if input.Min != nil && input.Max != nil && *input.Min > *input.Max {
+		problems = append(problems, "min is greater than max")
+	}
+	if input.Pattern != "" {
+		if _, err := regexp.Compile(input.Pattern); err != nil {
+			problems = append(problems, fmt.Sprintf("invalid pattern: %v", err))
+		}
+	}
+	if input.Required && input.Optional {
+		problems = append(problems, "cannot be both required and optional")
+	}
+	if input.Default != "" {
+		value := input.Default
+		if input.Type == InputDateTime {
+			if resolved, err := ResolveDate(value, input.DateFormat, time.Now()); err == nil {
+				value = resolved
+			}
+		}
+		if err := input.Validate(value); err != nil {
+			problems = append(problems, fmt.Sprintf("default is invalid: %v", err))
+		}
+	}
+	return problems
+}
+
+// sampleValue is a valid value for an input, used to render a template in
+// full when checking it
+func sampleValue(input Input, now time.Time) string {
+	if input.Default != "" {
+		return input.Default
+	}
+	if len(input.Options) > 0 {
+		return strings.TrimSpace(input.Options[0])
+	}
+	switch input.Type {
+	case InputNumber:
+		if input.Min != nil {
+			return formatNumber(*input.Min)
+		}
+		return "1"
+	case InputDateTime:
+		return now.Format(DateLayout(input.DateFormat))
+	}
+	return "sample"
+}
+
+// splitFrontMatter returns the YAML between the leading --- lines
+func splitFrontMatter(content string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "", false
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "---" {
+			return strings.Join(lines[1:i], "\n"), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,46 @@
+package templates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateCheck(t *testing.T) {
+	required := []string{"id", "title", "status", "kind", "created"}
+
+	good, err := ParseTemplate("---\ntemplate:\n  inputs:\n    - name: estimate\n      type: number\n      description: Estimate\n---\n---\nid: {{ .id }}\ntitle: {{ .title }}\nstatus: {{ .status }}\nkind: {{ .kind }}\ncreated: {{ .created }}\nestimate: {{ .estimate }}\n---\n")
+	require.NoError(t, err)
+	assert.Empty(t, good.Check(required))
+
+	bad, err := ParseTemplate(`---
+template:
+  inputs:
+    - name: size
+      type: number
+      options: [s, m]
+      description: Size
+    - name: owner
+      required: true
+      optional: true
+      description: Owner
+---
+---
+id: {{ .id }}
+title: {{ .title }}
+---
+`)
+	require.NoError(t, err)
+	problems := bad.Check(required)
+	assert.Contains(t, problems, "input size: options only apply to string and strings inputs")
+	assert.Contains(t, problems, "input owner: cannot be both required and optional")
+	assert.Contains(t, problems, "never produces required field status")
+	assert.Contains(t, problems, "never produces required field kind")
+
+	invalid, err := ParseTemplate("---\ntemplate:\n  inputs: []\n---\n---\ntitle: {{ .title }}: more\n---\n")
+	require.NoError(t, err)
+	problems = invalid.Check(nil)
+	require.NotEmpty(t, problems)
+	assert.Contains(t, problems[0], "front matter is not valid YAML")
+}
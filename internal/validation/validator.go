@@ -12,6 +12,7 @@ import (
 
     "gopkg.in/yaml.v3"
 
+    "kira/internal/archive"
     "kira/internal/config"
 )
 
@@ -58,19 +59,104 @@ type WorkItem struct {
 }
 
 func ValidateWorkItems(cfg *config.Config) (*ValidationResult, error) {
-	result := &ValidationResult{}
-
 	// Get all work item files
 	files, err := getWorkItemFiles()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get work item files: %w", err)
 	}
 
+	return validateFiles(cfg, files, nil, os.ReadFile, true)
+}
+
+// ValidateWorkItemFiles validates only the given work item files, such as
+// the ones staged for a commit. Duplicate IDs are still checked against the
+// whole workspace, and the workflow rules only when a file is in doing.
+func ValidateWorkItemFiles(cfg *config.Config, files []string) (*ValidationResult, error) {
+	all, err := getWorkItemFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work item files: %w", err)
+	}
+
+	selected, workflow := selectFiles(cfg, files)
+	return validateFiles(cfg, all, selected, os.ReadFile, workflow)
+}
+
+// ValidateWorkItemContents validates work item contents keyed by path, such
+// as the versions staged for a commit, in place of the files on disk. Other
+// files are checked as ValidateWorkItemFiles does.
+func ValidateWorkItemContents(cfg *config.Config, contents map[string][]byte) (*ValidationResult, error) {
+	all, err := getWorkItemFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work item files: %w", err)
+	}
+
+	byPath := make(map[string][]byte)
+	var files []string
+	for file, content := range contents {
+		if !isWorkItemPath(file) {
+			continue
+		}
+		byPath[filepath.Clean(file)] = content
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	// Staged files may be gone from the working tree
+	onDisk := make(map[string]bool)
+	for _, file := range all {
+		onDisk[filepath.Clean(file)] = true
+	}
+	for _, file := range files {
+		if !onDisk[filepath.Clean(file)] {
+			all = append(all, file)
+		}
+	}
+
+	read := func(file string) ([]byte, error) {
+		if content, ok := byPath[filepath.Clean(file)]; ok {
+			return content, nil
+		}
+		return os.ReadFile(file)
+	}
+	selected, workflow := selectFiles(cfg, files)
+	return validateFiles(cfg, all, selected, read, workflow)
+}
+
+// selectFiles is the set of files to validate, and whether one is in doing
+// so the workflow rules apply
+func selectFiles(cfg *config.Config, files []string) (map[string]bool, bool) {
+	selected := make(map[string]bool)
+	workflow := false
+	doingPath := filepath.Join(".work", cfg.StatusFolders["doing"])
+	for _, file := range files {
+		file = filepath.Clean(file)
+		selected[file] = true
+		if filepath.Dir(file) == doingPath {
+			workflow = true
+		}
+	}
+	return selected, workflow
+}
+
+// validateFiles validates the files in selected, or all files when selected
+// is nil, reading each with read
+func validateFiles(cfg *config.Config, files []string, selected map[string]bool, read func(string) ([]byte, error), workflow bool) (*ValidationResult, error) {
+	result := &ValidationResult{}
+
 	// Track IDs for duplicate checking
 	idMap := make(map[string][]string)
 
 	for _, file := range files {
-		workItem, err := parseWorkItemFile(file)
+		var workItem *WorkItem
+		content, err := read(file)
+		if err == nil {
+			workItem, err = parseWorkItem(content)
+		}
+		if selected != nil && !selected[filepath.Clean(file)] {
+			if err == nil {
+				idMap[workItem.ID] = append(idMap[workItem.ID], file)
+			}
+			continue
+		}
 		if err != nil {
 			result.AddError(file, fmt.Sprintf("failed to parse file: %v", err))
 			continue
@@ -103,13 +189,20 @@ func ValidateWorkItems(cfg *config.Config) (*ValidationResult, error) {
 	// Check for duplicate IDs
 	for id, files := range idMap {
 		if len(files) > 1 {
-			result.AddError(files[0], fmt.Sprintf("duplicate ID found: %s in files %s", id, strings.Join(files, ", ")))
+			for _, file := range files {
+				if selected == nil || selected[filepath.Clean(file)] {
+					result.AddError(file, fmt.Sprintf("duplicate ID found: %s in files %s", id, strings.Join(files, ", ")))
+					break
+				}
+			}
 		}
 	}
 
 	// Validate workflow rules
-	if err := validateWorkflowRules(cfg); err != nil {
-		result.AddError("workflow", err.Error())
+	if workflow {
+		if err := validateWorkflowRules(cfg); err != nil {
+			result.AddError("workflow", err.Error())
+		}
 	}
 
 	return result, nil
@@ -123,29 +216,35 @@ func getWorkItemFiles() ([]string, error) {
 			return err
 		}
 
-		// Skip directories and non-markdown files
-		if info.IsDir() || !strings.HasSuffix(path, ".md") {
-			return nil
-		}
-
-		// Skip template files and IDEAS.md
-		if strings.Contains(path, "template") || strings.HasSuffix(path, "IDEAS.md") {
-			return nil
+		if !info.IsDir() && isWorkItemPath(path) {
+			files = append(files, path)
 		}
-
-		files = append(files, path)
 		return nil
 	})
 
 	return files, err
 }
 
-func parseWorkItemFile(filePath string) (*WorkItem, error) {
+// isWorkItemPath reports whether a markdown file under .work is a work item
+// rather than a template, IDEAS.md or a compacted archive bundle
+func isWorkItemPath(path string) bool {
+	return strings.HasSuffix(path, ".md") &&
+		!strings.Contains(path, "template") &&
+		!strings.HasSuffix(path, "IDEAS.md") &&
+		!archive.IsBundle(path)
+}
+
+// ParseWorkItemFile reads a work item and decodes its YAML front matter
+func ParseWorkItemFile(filePath string) (*WorkItem, error) {
     content, err := os.ReadFile(filePath)
     if err != nil {
         return nil, err
     }
+    return parseWorkItem(content)
+}
 
+// parseWorkItem decodes the YAML front matter of work item content
+func parseWorkItem(content []byte) (*WorkItem, error) {
     // Extract YAML front matter between the first pair of --- lines
     lines := strings.Split(string(content), "\n")
     var yamlLines []string
@@ -276,7 +375,7 @@ func GetNextID() (string, error) {
 
 	var maxID int
 	for _, file := range files {
-		workItem, err := parseWorkItemFile(file)
+		workItem, err := ParseWorkItemFile(file)
 		if err != nil {
 			continue
 		}
@@ -288,10 +387,36 @@ func GetNextID() (string, error) {
 		}
 	}
 
+	// IDs of compacted archive items are still taken
+	for _, id := range bundledIDs() {
+		if n, err := strconv.Atoi(id); err == nil && n > maxID {
+			maxID = n
+		}
+	}
+
 	nextID := maxID + 1
 	return fmt.Sprintf("%03d", nextID), nil
 }
 
+// bundledIDs returns the IDs recorded in every archive bundle under .work
+func bundledIDs() []string {
+	var ids []string
+	_ = filepath.Walk(".work", func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !archive.IsBundle(path) {
+			return nil
+		}
+		records, err := archive.ReadBundle(path)
+		if err != nil {
+			return nil
+		}
+		for _, r := range records {
+			ids = append(ids, r.ID)
+		}
+		return nil
+	})
+	return ids
+}
+
 func FixDuplicateIDs() (*ValidationResult, error) {
 	result := &ValidationResult{}
 
@@ -303,7 +428,7 @@ func FixDuplicateIDs() (*ValidationResult, error) {
 	// Group files by ID
 	idGroups := make(map[string][]string)
 	for _, file := range files {
-		workItem, err := parseWorkItemFile(file)
+		workItem, err := ParseWorkItemFile(file)
 		if err != nil {
 			continue
 		}
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"fmt"
 	"os"
 	"testing"
 
@@ -74,6 +75,57 @@ created: 2024-01-01
 	})
 }
 
+func TestValidateWorkItemFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+
+	os.MkdirAll(".work/1_todo", 0755)
+	item := "---\nid: %s\ntitle: T\nstatus: todo\nkind: task\ncreated: 2024-01-01\n---\n"
+	os.WriteFile(".work/1_todo/001-a.task.md", []byte(fmt.Sprintf(item, "001")), 0644)
+	os.WriteFile(".work/1_todo/002-b.task.md", []byte("---\nid: 002\n---\n"), 0644)
+	os.WriteFile(".work/1_todo/001-c.task.md", []byte(fmt.Sprintf(item, "001")), 0644)
+
+	cfg := &config.DefaultConfig
+	result, err := ValidateWorkItemFiles(cfg, []string{".work/1_todo/001-c.task.md"})
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1, "only the given file is checked, against every ID")
+	assert.Equal(t, ".work/1_todo/001-c.task.md", result.Errors[0].File)
+	assert.Contains(t, result.Errors[0].Message, "duplicate ID found: 001")
+
+	result, err = ValidateWorkItemFiles(cfg, []string{".work/1_todo/002-b.task.md"})
+	require.NoError(t, err)
+	assert.True(t, result.HasErrors())
+	for _, e := range result.Errors {
+		assert.Equal(t, ".work/1_todo/002-b.task.md", e.File)
+	}
+}
+
+func TestValidateWorkItemContents(t *testing.T) {
+	tmpDir := t.TempDir()
+	os.Chdir(tmpDir)
+	defer os.Chdir("/")
+
+	os.MkdirAll(".work/1_todo", 0755)
+	item := "---\nid: %s\ntitle: T\nstatus: todo\nkind: task\ncreated: 2024-01-01\n---\n"
+	os.WriteFile(".work/1_todo/001-a.task.md", []byte(fmt.Sprintf(item, "001")), 0644)
+	os.WriteFile(".work/1_todo/002-b.task.md", []byte(fmt.Sprintf(item, "002")), 0644)
+
+	cfg := &config.DefaultConfig
+	result, err := ValidateWorkItemContents(cfg, map[string][]byte{".work/1_todo/002-b.task.md": []byte("---\nid: 002\n---\n")})
+	require.NoError(t, err)
+	assert.True(t, result.HasErrors(), "the given content is checked, not the file")
+
+	result, err = ValidateWorkItemContents(cfg, map[string][]byte{
+		".work/1_todo/003-c.task.md":  []byte(fmt.Sprintf(item, "001")),
+		".work/templates/template.md": []byte("not a work item"),
+	})
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1, "files missing from disk are checked too; templates are not")
+	assert.Equal(t, ".work/1_todo/003-c.task.md", result.Errors[0].File)
+	assert.Contains(t, result.Errors[0].Message, "duplicate ID found: 001")
+}
+
 func TestGetNextID(t *testing.T) {
 	t.Run("generates first ID when no work items exist", func(t *testing.T) {
 		// Create a temporary workspace